@@ -0,0 +1,129 @@
+package netbox
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client"
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/mutexkv"
+)
+
+// netboxMutexKV serializes allocations from the same parent object within an
+// apply. Netbox computes the next free address or prefix without locking, so
+// concurrent requests against one parent would be handed the same one.
+var netboxMutexKV = mutexkv.NewMutexKV()
+
+// allocationLockKey returns the key under which allocations from an object
+// are serialized, such as "ipam/prefix/42".
+func allocationLockKey(objectType string, id int) string {
+	return objectType + "/" + strconv.Itoa(id)
+}
+
+// containingPrefix returns the ID of the most specific prefix in vrf, nil for
+// the global table, containing the address or prefix cidr. This is the prefix
+// an imported allocation is assumed to come from. With strict, cidr itself
+// does not count.
+func containingPrefix(c *ProviderNetboxClient, cidr string, vrf *models.NestedVRF, strict bool) (int64, error) {
+	addr, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 0, err
+	}
+	length, _ := network.Mask.Size()
+
+	query := url.Values{}
+	query.Set("contains", addr.String())
+	query.Set("limit", "0")
+	if vrf != nil {
+		query.Set("vrf_id", strconv.FormatInt(vrf.ID, 10))
+	} else {
+		query.Set("vrf_id", "null")
+	}
+
+	var prefixes struct {
+		Results []models.Prefix `json:"results"`
+	}
+	if err := c.doJSON(http.MethodGet, "/ipam/prefixes/?"+query.Encode(), nil, &prefixes); err != nil {
+		return 0, err
+	}
+
+	var (
+		prefixID int64
+		longest  = -1
+	)
+	for _, p := range prefixes.Results {
+		if p.Prefix == nil {
+			continue
+		}
+		_, candidate, err := net.ParseCIDR(*p.Prefix)
+		if err != nil {
+			continue
+		}
+		ones, _ := candidate.Mask.Size()
+		if strict && ones >= length {
+			continue
+		}
+		if ones > longest {
+			prefixID, longest = p.ID, ones
+		}
+	}
+
+	if prefixID == 0 {
+		return 0, fmt.Errorf("no prefix contains %s", cidr)
+	}
+
+	return prefixID, nil
+}
+
+// vlanListPageSize is the number of VLANs fetched per request when looking
+// for a free VID.
+const vlanListPageSize = 1000
+
+// nextAvailableVID returns the lowest VID between min and max not used by a
+// VLAN of the group. Netbox has no endpoint for this, so the VLANs of the
+// group are listed.
+func nextAvailableVID(c *client.NetBox, groupID, min, max int) (int, error) {
+	used := map[int64]bool{}
+
+	group := strconv.Itoa(groupID)
+	limit := int64(vlanListPageSize)
+
+	// Netbox may return less than asked for, the offset follows what came back.
+	for offset := int64(0); ; {
+		param := ipam.NewIPAMVlansListParams().WithGroupID(&group).WithLimit(&limit).WithOffset(&offset)
+
+		out, err := c.IPAM.IPAMVlansList(param, nil)
+
+		if err != nil {
+			log.Debugf("Failed to execute IPAMVlansList: %v", err)
+
+			return 0, err
+		}
+
+		for _, vlan := range out.Payload.Results {
+			if vlan.Vid != nil {
+				used[*vlan.Vid] = true
+			}
+		}
+
+		offset += int64(len(out.Payload.Results))
+
+		if len(out.Payload.Results) == 0 || out.Payload.Count == nil || offset >= *out.Payload.Count {
+			break
+		}
+	}
+
+	for vid := min; vid <= max; vid++ {
+		if !used[int64(vid)] {
+			return vid, nil
+		}
+	}
+
+	return 0, fmt.Errorf("VLAN group %d has no free VID between %d and %d", groupID, min, max)
+}
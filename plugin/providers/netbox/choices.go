@@ -0,0 +1,169 @@
+package netbox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// choiceSet maps the integer values of a Netbox choice field, as used by the
+// 2.5 API and the pinned client, to the slugs used since Netbox 2.7.
+type choiceSet map[int64]string
+
+func (c choiceSet) slug(value int64) (string, bool) {
+	s, ok := c[value]
+	return s, ok
+}
+
+func (c choiceSet) value(slug string) (int64, bool) {
+	for v, s := range c {
+		if s == slug {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// parse accepts either the integer or the slug form of a choice.
+func (c choiceSet) parse(raw string) (int64, error) {
+	if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		if _, ok := c[v]; ok {
+			return v, nil
+		}
+	} else if v, ok := c.value(strings.ToLower(raw)); ok {
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("%q is not one of %s", raw, strings.Join(c.names(), ", "))
+}
+
+// names lists every accepted form, integer and slug, in integer order.
+func (c choiceSet) names() []string {
+	var names []string
+	for v := int64(0); len(names) < 2*len(c); v++ {
+		if s, ok := c[v]; ok {
+			names = append(names, strconv.FormatInt(v, 10), s)
+		}
+	}
+	return names
+}
+
+// validate is a schema.SchemaValidateFunc accepting either form of a choice.
+func (c choiceSet) validate(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := c.parse(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %s", k, err))
+	}
+	return
+}
+
+// filter returns the form of a choice Netbox filters on in the given
+// version: the slug since Netbox 2.7, the integer before or when the version
+// is unknown, as in request bodies.
+func (c choiceSet) filter(raw string, v apiVersion) (string, error) {
+	value, err := c.parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if featureChoiceSlugs.knownBy(v) {
+		slug, _ := c.slug(value)
+		return slug, nil
+	}
+	return strconv.FormatInt(value, 10), nil
+}
+
+// validateValue is a schema.SchemaValidateFunc for integer attributes, which
+// only accept the integer form of a choice.
+func (c choiceSet) validateValue(v interface{}, k string) (ws []string, errors []error) {
+	if _, ok := c[int64(v.(int))]; !ok {
+		var values []string
+		for _, name := range c.names() {
+			if _, err := strconv.Atoi(name); err == nil {
+				values = append(values, name)
+			}
+		}
+		errors = append(errors, fmt.Errorf("%s: %d is not one of %s", k, v, strings.Join(values, ", ")))
+	}
+	return
+}
+
+// suppressEquivalent is a schema.SchemaDiffSuppressFunc ignoring changes
+// between the integer and slug forms of the same choice.
+func (c choiceSet) suppressEquivalent(k, old, new string, d *schema.ResourceData) bool {
+	o, err := c.parse(old)
+	if err != nil {
+		return false
+	}
+	n, err := c.parse(new)
+	return err == nil && o == n
+}
+
+var (
+	prefixStatusChoices = choiceSet{
+		0: "container",
+		1: "active",
+		2: "reserved",
+		3: "deprecated",
+	}
+
+	ipAddressStatusChoices = choiceSet{
+		1: "active",
+		2: "reserved",
+		3: "deprecated",
+		5: "dhcp",
+	}
+
+	ipAddressRoleChoices = choiceSet{
+		10: "loopback",
+		20: "secondary",
+		30: "anycast",
+		40: "vip",
+		41: "vrrp",
+		42: "hsrp",
+		43: "glbp",
+		44: "carp",
+	}
+
+	vlanStatusChoices = choiceSet{
+		1: "active",
+		2: "reserved",
+		3: "deprecated",
+	}
+
+	serviceProtocolChoices = choiceSet{
+		6:  "tcp",
+		17: "udp",
+	}
+
+	// familyChoices validates the family filters of lookups, which Netbox
+	// takes as 4 or 6.
+	familyChoices = choiceSet{
+		4: "ipv4",
+		6: "ipv6",
+	}
+)
+
+// choiceFields lists the choice fields of each object type, keyed by the API
+// path segment identifying the type.
+var choiceFields = []struct {
+	path   string
+	fields map[string]choiceSet
+}{
+	// Allocation endpoints come first, they are nested under other types.
+	{"/available-ips/", map[string]choiceSet{"status": ipAddressStatusChoices, "role": ipAddressRoleChoices}},
+	{"/available-prefixes/", map[string]choiceSet{"status": prefixStatusChoices}},
+	{"/ipam/prefixes/", map[string]choiceSet{"status": prefixStatusChoices}},
+	{"/ipam/ip-addresses/", map[string]choiceSet{"status": ipAddressStatusChoices, "role": ipAddressRoleChoices}},
+	{"/ipam/vlans/", map[string]choiceSet{"status": vlanStatusChoices}},
+	{"/ipam/services/", map[string]choiceSet{"protocol": serviceProtocolChoices}},
+}
+
+func choiceFieldsForPath(path string) map[string]choiceSet {
+	for _, c := range choiceFields {
+		if strings.Contains(path, c.path) {
+			return c.fields
+		}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package netbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compatTransport lets the pinned client, which speaks the Netbox 2.5 API,
+// talk to newer servers by rewriting request and response payloads:
+//
+//   - choice fields are sent as slugs and read back as integers, see
+//     featureChoiceSlugs
+//   - tags are sent as nested objects and read back as names, see
+//     featureNestedTags
+//   - IP address interfaces are sent and read back as assigned objects, see
+//     featureAssignedObject
+//
+// It also adds the fields registered with withExplicitFields to request
+// payloads, whatever the server version.
+type compatTransport struct {
+	next    http.RoundTripper
+	version *serverVersion
+}
+
+// objectRewriter modifies a single top level object of an API payload.
+type objectRewriter func(obj map[string]interface{}) error
+
+// RoundTrip implements http.RoundTripper.
+func (t *compatTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rewrite := t.requestRewriter(req); rewrite != nil && req.Body != nil && req.Body != http.NoBody {
+		rewritten, err := rewriteRequest(req, rewrite)
+		if err != nil {
+			return nil, err
+		}
+		req = rewritten
+	}
+
+	resp, err := t.next.RoundTrip(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.version.learn(resp.Header)
+
+	rewrite := t.responseRewriter(req.URL.Path)
+
+	if rewrite == nil || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return resp, nil
+	}
+
+	if err := rewriteResponse(resp, rewrite); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// supports reports whether the server is known to provide the feature.
+func (t *compatTransport) supports(f apiFeature) bool {
+	return f.knownBy(t.version.get())
+}
+
+func (t *compatTransport) requestRewriter(req *http.Request) objectRewriter {
+	var rewriters []objectRewriter
+
+	// Explicit fields go first so choices among them are translated too.
+	explicit, _ := req.Context().Value(explicitFieldsKey{}).(map[string]interface{})
+	if explicit != nil {
+		rewriters = append(rewriters, func(obj map[string]interface{}) error {
+			for name, value := range explicit {
+				if _, ok := obj[name]; !ok {
+					obj[name] = value
+				}
+			}
+			return nil
+		})
+	}
+
+	if fields := choiceFieldsForPath(req.URL.Path); fields != nil && t.supports(featureChoiceSlugs) {
+		rewriters = append(rewriters, func(obj map[string]interface{}) error {
+			for name, choices := range fields {
+				if n, ok := obj[name].(json.Number); ok {
+					if v, err := n.Int64(); err == nil {
+						if slug, ok := choices.slug(v); ok {
+							obj[name] = slug
+						}
+					}
+				}
+			}
+			return nil
+		})
+	}
+
+	if t.supports(featureNestedTags) {
+		rewriters = append(rewriters, func(obj map[string]interface{}) error {
+			if tags, ok := obj["tags"].([]interface{}); ok {
+				for i, tag := range tags {
+					if name, ok := tag.(string); ok {
+						tags[i] = map[string]interface{}{"name": name}
+					}
+				}
+			}
+			return nil
+		})
+	}
+
+	// Only the resource knows whether an interface belongs to a device or a
+	// VM, it sends assigned_object_type as an explicit field next to it.
+	if _, ok := explicit["assigned_object_type"]; ok {
+		assignedObject := t.supports(featureAssignedObject)
+		rewriters = append(rewriters, func(obj map[string]interface{}) error {
+			if assignedObject {
+				obj["assigned_object_id"] = obj["interface"]
+				delete(obj, "interface")
+			} else {
+				delete(obj, "assigned_object_type")
+			}
+			return nil
+		})
+	}
+
+	return chainRewriters(rewriters)
+}
+
+func (t *compatTransport) responseRewriter(path string) objectRewriter {
+	var rewriters []objectRewriter
+
+	if fields := choiceFieldsForPath(path); fields != nil && t.supports(featureChoiceSlugs) {
+		rewriters = append(rewriters, func(obj map[string]interface{}) error {
+			for name, choices := range fields {
+				choice, ok := obj[name].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				slug, ok := choice["value"].(string)
+				if !ok {
+					continue
+				}
+				value, ok := choices.value(slug)
+				if !ok {
+					return fmt.Errorf("Netbox returned %s %q, which is not supported by this provider", name, slug)
+				}
+				choice["value"] = value
+			}
+			return nil
+		})
+	}
+
+	if t.supports(featureNestedTags) {
+		rewriters = append(rewriters, func(obj map[string]interface{}) error {
+			if tags, ok := obj["tags"].([]interface{}); ok {
+				for i, tag := range tags {
+					if nested, ok := tag.(map[string]interface{}); ok {
+						tags[i] = nested["name"]
+					}
+				}
+			}
+			return nil
+		})
+	}
+
+	if t.supports(featureAssignedObject) {
+		rewriters = append(rewriters, func(obj map[string]interface{}) error {
+			assigned, ok := obj["assigned_object"].(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			iface := map[string]interface{}{}
+			for _, name := range []string{"id", "url", "name", "device", "virtual_machine"} {
+				if v, ok := assigned[name]; ok {
+					iface[name] = v
+				}
+			}
+			obj["interface"] = iface
+			return nil
+		})
+	}
+
+	return chainRewriters(rewriters)
+}
+
+type explicitFieldsKey struct{}
+
+// withExplicitFields returns a context under which requests carry the given
+// fields when the pinned client left them out. Its models omit zero values,
+// so this is how choice 0 is sent or an optional relation cleared with nil.
+func withExplicitFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	encoded := map[string]interface{}{}
+	for name, value := range fields {
+		if v, ok := value.(int64); ok {
+			value = json.Number(strconv.FormatInt(v, 10))
+		}
+		encoded[name] = value
+	}
+	return context.WithValue(ctx, explicitFieldsKey{}, encoded)
+}
+
+func chainRewriters(rewriters []objectRewriter) objectRewriter {
+	if len(rewriters) == 0 {
+		return nil
+	}
+
+	return func(obj map[string]interface{}) error {
+		for _, rewrite := range rewriters {
+			if err := rewrite(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func rewriteRequest(req *http.Request, rewrite objectRewriter) (*http.Request, error) {
+	payload, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if doc, err := decodeJSON(payload); err == nil {
+		if err := walkObjects(doc, rewrite); err != nil {
+			return nil, err
+		}
+		if payload, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	clone := req.WithContext(req.Context())
+	clone.Body = ioutil.NopCloser(bytes.NewReader(payload))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(payload)), nil
+	}
+	clone.ContentLength = int64(len(payload))
+
+	return clone, nil
+}
+
+func rewriteResponse(resp *http.Response, rewrite objectRewriter) error {
+	payload, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	if doc, err := decodeJSON(payload); err == nil {
+		if err := walkObjects(doc, rewrite); err != nil {
+			return err
+		}
+		if payload, err = json.Marshal(doc); err != nil {
+			return err
+		}
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(payload))
+	resp.ContentLength = int64(len(payload))
+	resp.Header.Del("Content-Length")
+
+	return nil
+}
+
+// walkObjects calls fn for the top level object(s) of an API payload: a
+// single object, an array of objects, or a paginated list.
+func walkObjects(doc interface{}, fn objectRewriter) error {
+	switch v := doc.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				if err := fn(obj); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		if results, ok := v["results"].([]interface{}); ok {
+			return walkObjects(results, fn)
+		}
+		return fn(v)
+	}
+	return nil
+}
+
+// decodeJSON decodes a payload keeping numbers as they were sent.
+func decodeJSON(payload []byte) (interface{}, error) {
+	var doc interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&doc)
+
+	return doc, err
+}
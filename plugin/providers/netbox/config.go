@@ -1,13 +1,15 @@
 package netbox
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
-	"strings"
+	"net/http"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
-	"net/url"
-
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/strfmt"
 
 	"github.com/tpretz/go-netbox/netbox/client"
@@ -22,30 +24,145 @@ type Config struct {
 	// environment variable.
 	AppID string
 
-	// The API endpoint. This defaults to http://localhost/api, and can also be
-	// supplied via the NETBOX_ENDPOINT_ADDR environment variable.
+	// Alternatives to AppID: a file holding the token, or a command (and its
+	// arguments) printing the token on stdout. Only one may be set.
+	AppIDFile    string
+	AppIDCommand []string
+
+	// The API endpoint, with or without the trailing /api. Bare host names
+	// default to http, and instances served under a sub-path are supported.
+	// It can also be supplied via the NETBOX_ENDPOINT_ADDR environment variable.
 	Endpoint string
+
+	// Path to a PEM encoded CA bundle used to verify the Netbox server
+	// certificate. The system trust store is used when empty.
+	CACertFile string
+
+	// Paths to a PEM encoded client certificate and private key presented to
+	// Netbox for mutual TLS. Both must be supplied together.
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// Disables verification of the Netbox server certificate.
+	Insecure bool
+
+	// Timeout applied to each individual API request attempt.
+	RequestTimeout time.Duration
+
+	// Number of times a request failing with a transient error is retried.
+	MaxRetries int
+
+	// Bounds of the exponential backoff between retries.
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
+	// Maximum number of requests per second sent to Netbox, zero is unlimited.
+	RateLimit float64
+
+	// Maximum number of requests in flight at once, zero is unlimited.
+	MaxConcurrentRequests int
+
+	// Tags added to every object created or updated by the provider.
+	DefaultTags []string
 }
 
 type ProviderNetboxClient struct {
 	client        *client.NetBox
 	configuration Config
+
+	// Location of the API and the HTTP client behind client, for requests
+	// the generated client does not cover.
+	endpoint   *apiEndpoint
+	httpClient *http.Client
+
+	// Shared by every request issued through client, nil when unlimited.
+	limiter *requestLimiter
+
+	// API version of the Netbox server, see apiVersion.
+	version *serverVersion
+
+	// Tags merged into the tags of every managed object.
+	defaultTags []string
+
+	// Custom field definitions, loaded on first use.
+	customFields customFieldCache
+}
+
+// tlsConfig builds the TLS client configuration from the CA bundle, client
+// certificate and insecure settings.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		return nil, fmt.Errorf("client_cert_file and client_key_file must be set together")
+	}
+
+	return openapi_runtimeclient.TLSClientAuth(openapi_runtimeclient.TLSClientOptions{
+		CA:                 c.CACertFile,
+		Certificate:        c.ClientCertFile,
+		Key:                c.ClientKeyFile,
+		InsecureSkipVerify: c.Insecure,
+	})
+}
+
+// transport returns the HTTP transport shared by every request issued against Netbox.
+func (c *Config) transport(limiter *requestLimiter) (http.RoundTripper, error) {
+	tlsConfig, err := c.tlsConfig()
+
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return newRetryTransport(transport, c.RequestTimeout, c.MaxRetries, c.RetryWaitMin, c.RetryWaitMax, limiter), nil
+}
+
+// deadlineFreeTransport submits the operations of the generated client
+// without the deadline the runtime derives from the timeout their parameters
+// carry. The retry transport bounds every attempt and the backoff between
+// them, while time spent queued behind the rate limit or concurrency cap must
+// not count against any deadline.
+type deadlineFreeTransport struct {
+	runtime.ClientTransport
+}
+
+func (t deadlineFreeTransport) Submit(operation *runtime.ClientOperation) (interface{}, error) {
+	if operation.Context != nil {
+		return t.ClientTransport.Submit(operation)
+	}
+
+	op := *operation
+	op.Context = context.Background()
+
+	return t.ClientTransport.Submit(&op)
 }
 
 // Client does the heavy lifting of establishing a base Open API client to Netbox.
 func (c *Config) Client() (interface{}, error) {
-	cfg := Config{
-		AppID:    c.AppID,
-		Endpoint: c.Endpoint,
+	cfg := *c
+
+	appID, err := cfg.resolveAppID()
+
+	if err != nil {
+		log.WithFields(
+			log.Fields{
+				"app_id_file": cfg.AppIDFile,
+				"error":       err,
+			},
+		).Error("Failed to resolve API token")
+
+		return nil, err
 	}
 
+	cfg.AppID = appID
+
 	log.WithFields(
 		log.Fields{
 			"uri": cfg.Endpoint,
 		},
 	).Debug("Initializing Netbox client")
 
-	parsedURI, uriParseError := url.ParseRequestURI(cfg.Endpoint)
+	apiEndpoint, uriParseError := parseEndpoint(cfg.Endpoint)
 
 	if uriParseError != nil {
 		log.WithFields(
@@ -58,31 +175,77 @@ func (c *Config) Client() (interface{}, error) {
 		return nil, uriParseError
 	}
 
-	parsedScheme := strings.ToLower(parsedURI.Scheme)
+	desiredRuntimeClientSchemes := []string{apiEndpoint.Scheme}
 
-	if parsedScheme == "" {
-		parsedScheme = "http"
-	}
+	limiter := newRequestLimiter(cfg.RateLimit, cfg.MaxConcurrentRequests, realClock{})
+
+	transport, err := cfg.transport(limiter)
 
-	desiredRuntimeClientSchemes := []string{parsedScheme}
+	if err != nil {
+		log.WithFields(
+			log.Fields{
+				"ca_cert_file":     cfg.CACertFile,
+				"client_cert_file": cfg.ClientCertFile,
+				"error":            err,
+			},
+		).Error("Failed to configure TLS")
+
+		return nil, err
+	}
 
 	log.WithFields(
 		log.Fields{
-			"host":    parsedURI.Host,
-			"schemes": desiredRuntimeClientSchemes,
+			"host":            apiEndpoint.Host,
+			"base_path":       apiEndpoint.BasePath,
+			"schemes":         desiredRuntimeClientSchemes,
+			"insecure":        cfg.Insecure,
+			"request_timeout": cfg.RequestTimeout,
+			"max_retries":     cfg.MaxRetries,
+			"rate_limit":      cfg.RateLimit,
+			"max_concurrent":  cfg.MaxConcurrentRequests,
 		},
 	).Debug("Initializing open API runtime client")
 
-	runtimeClient := openapi_runtimeclient.New(parsedURI.Host, client.DefaultBasePath, desiredRuntimeClientSchemes)
+	version := &serverVersion{}
+
+	httpClient := &http.Client{Transport: &compatTransport{next: transport, version: version}}
+
+	detectedVersion, err := detectAPIVersion(httpClient, apiEndpoint, cfg.AppID)
+
+	if err != nil {
+		// Not fatal, the version is learnt from the first API response instead.
+		log.WithFields(
+			log.Fields{
+				"uri":   apiEndpoint.String(),
+				"error": err,
+			},
+		).Warn("Failed to detect Netbox API version")
+	} else {
+		version.set(detectedVersion)
+
+		log.WithFields(
+			log.Fields{
+				"api_version":    detectedVersion.String(),
+				"client_version": clientAPIVersion.String(),
+			},
+		).Info("Detected Netbox API version")
+	}
+
+	runtimeClient := openapi_runtimeclient.NewWithClient(apiEndpoint.Host, apiEndpoint.BasePath, desiredRuntimeClientSchemes, httpClient)
 
 	runtimeClient.DefaultAuthentication = openapi_runtimeclient.APIKeyAuth("Authorization", "header", fmt.Sprintf("Token %v", cfg.AppID))
-	runtimeClient.SetLogger(log.StandardLogger())
+	runtimeClient.SetLogger(redactingLogger{Logger: log.StandardLogger(), secret: cfg.AppID})
 
-	netboxClient := client.New(runtimeClient, strfmt.Default)
+	netboxClient := client.New(deadlineFreeTransport{ClientTransport: runtimeClient}, strfmt.Default)
 
 	terraformNetboxClient := ProviderNetboxClient{
 		client:        netboxClient,
 		configuration: cfg,
+		endpoint:      apiEndpoint,
+		httpClient:    httpClient,
+		limiter:       limiter,
+		version:       version,
+		defaultTags:   cfg.DefaultTags,
 	}
 
 	return &terraformNetboxClient, nil
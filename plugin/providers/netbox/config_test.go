@@ -0,0 +1,165 @@
+package netbox
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+)
+
+// testNetboxTLSServer starts a TLS stand-in for Netbox that answers every
+// request with an empty result list.
+func testNetboxTLSServer(t *testing.T, clientAuth tls.ClientAuthType) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if clientAuth != tls.NoClientCert && len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 0, "results": []}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: clientAuth}
+	server.StartTLS()
+
+	return server
+}
+
+// testWritePEM writes a single PEM block into dir and returns the file path.
+func testWritePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return path
+}
+
+func testTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "terraform-provider-netbox")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return dir
+}
+
+func testConfigListRirs(t *testing.T, cfg Config) error {
+	meta, err := cfg.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err = meta.(*ProviderNetboxClient).client.IPAM.IPAMRirsList(ipam.NewIPAMRirsListParams(), nil)
+	return err
+}
+
+func TestConfigClient_untrustedCA(t *testing.T) {
+	server := testNetboxTLSServer(t, tls.NoClientCert)
+	defer server.Close()
+
+	if err := testConfigListRirs(t, Config{Endpoint: server.URL}); err == nil {
+		t.Fatal("expected certificate verification error")
+	}
+}
+
+func TestConfigClient_caCertFile(t *testing.T) {
+	server := testNetboxTLSServer(t, tls.NoClientCert)
+	defer server.Close()
+
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	cfg := Config{
+		Endpoint:   server.URL,
+		CACertFile: testWritePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw),
+	}
+
+	if err := testConfigListRirs(t, cfg); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigClient_insecure(t *testing.T) {
+	server := testNetboxTLSServer(t, tls.NoClientCert)
+	defer server.Close()
+
+	if err := testConfigListRirs(t, Config{Endpoint: server.URL, Insecure: true}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestConfigClient_clientCertificate(t *testing.T) {
+	server := testNetboxTLSServer(t, tls.RequireAnyClientCert)
+	defer server.Close()
+
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	// Reuse the stand-in server's key pair as the client identity.
+	key, err := x509.MarshalPKCS8PrivateKey(server.TLS.Certificates[0].PrivateKey)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cfg := Config{
+		Endpoint:       server.URL,
+		CACertFile:     testWritePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw),
+		ClientCertFile: testWritePEM(t, dir, "client.pem", "CERTIFICATE", server.Certificate().Raw),
+		ClientKeyFile:  testWritePEM(t, dir, "client-key.pem", "PRIVATE KEY", key),
+	}
+
+	if err := testConfigListRirs(t, cfg); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cfg.ClientCertFile, cfg.ClientKeyFile = "", ""
+	if err := testConfigListRirs(t, cfg); err == nil {
+		t.Fatal("expected error without a client certificate")
+	}
+}
+
+func TestConfigClient_clientCertWithoutKey(t *testing.T) {
+	cfg := Config{Endpoint: "https://netbox.example.com", ClientCertFile: "client.pem"}
+
+	if _, err := cfg.Client(); err == nil {
+		t.Fatal("expected error when client_key_file is missing")
+	}
+}
+
+func TestConfigClient_queuedRequestsHaveNoDeadline(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/rirs/": {handler: func(testRequest) testRoute {
+			time.Sleep(40 * time.Millisecond)
+			return testRoute{body: `{"count": 0, "results": []}`}
+		}},
+	})
+	defer server.Close()
+
+	// Every attempt fits in the request timeout, but the last of the queued
+	// requests waits for longer than that behind the others.
+	cfg := Config{AppID: "0123456789abcdef", Endpoint: server.URL, RequestTimeout: 100 * time.Millisecond, MaxConcurrentRequests: 1}
+	meta, err := cfg.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c := meta.(*ProviderNetboxClient).client
+
+	errs := make(chan error, 4)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			_, err := c.IPAM.IPAMRirsList(ipam.NewIPAMRirsListParams(), nil)
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+}
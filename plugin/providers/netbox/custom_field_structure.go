@@ -1,9 +1,15 @@
 package netbox
 
 import (
-	// "fmt"
 	// "reflect"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
@@ -24,3 +30,316 @@ func customFieldFilterSchema(conflicts []string) *schema.Schema {
 		},
 	}
 }
+
+// customFieldsSchema returns the schema of the custom_fields attribute shared
+// by every resource whose Netbox object has custom fields.
+func customFieldsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Custom field values by field name. Fields not listed here are left untouched.",
+	}
+}
+
+// customField is the definition of a custom field, as far as Netbox reports
+// it.
+type customField struct {
+	// Type is the field type, such as "boolean" or "select". Before Netbox
+	// 2.10 only selection fields are known, as "select".
+	Type string
+
+	// ChoiceIDs are the choices of a selection field by label, before Netbox
+	// 2.10.
+	ChoiceIDs map[string]int64
+
+	// Choices are the values of a selection field since Netbox 2.10.
+	Choices []string
+}
+
+// customFieldDefinitions holds the custom fields by name.
+type customFieldDefinitions struct {
+	Fields map[string]customField
+}
+
+// customFieldCache holds the custom field definitions once loaded.
+type customFieldCache struct {
+	mu          sync.Mutex
+	definitions *customFieldDefinitions
+}
+
+// customFieldDefinitions returns the custom field definitions, loading them
+// from Netbox on first use.
+func (c *ProviderNetboxClient) customFieldDefinitions() (*customFieldDefinitions, error) {
+	cache := &c.customFields
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.definitions != nil {
+		return cache.definitions, nil
+	}
+
+	var definitions *customFieldDefinitions
+	var err error
+
+	if featureCustomFieldDefinitions.supportedBy(c.apiVersion()) {
+		definitions, err = c.fetchCustomFields()
+	} else {
+		definitions, err = c.fetchCustomFieldChoices()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cache.definitions = definitions
+
+	return definitions, nil
+}
+
+// fetchCustomFields lists the custom fields from /extras/custom-fields/.
+func (c *ProviderNetboxClient) fetchCustomFields() (*customFieldDefinitions, error) {
+	results, err := c.doJSONList("/extras/custom-fields/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching custom fields: %s", err)
+	}
+
+	definitions := &customFieldDefinitions{Fields: map[string]customField{}}
+
+	for _, raw := range results {
+		var field struct {
+			Name string `json:"name"`
+			Type struct {
+				Value string `json:"value"`
+			} `json:"type"`
+			Choices []string `json:"choices"`
+		}
+		if err := json.Unmarshal(raw, &field); err != nil {
+			return nil, err
+		}
+
+		definitions.Fields[field.Name] = customField{Type: field.Type.Value, Choices: field.Choices}
+	}
+
+	return definitions, nil
+}
+
+// fetchCustomFieldChoices reads the choices of the selection custom fields,
+// by field name then label, from /extras/_custom_field_choices/. A server
+// without that endpoint has no selection field to resolve.
+func (c *ProviderNetboxClient) fetchCustomFieldChoices() (*customFieldDefinitions, error) {
+	definitions := &customFieldDefinitions{Fields: map[string]customField{}}
+
+	choices := map[string]map[string]int64{}
+	if err := c.doJSON(http.MethodGet, "/extras/_custom_field_choices/", nil, &choices); err != nil {
+		if isNotFound(err) {
+			return definitions, nil
+		}
+		return nil, fmt.Errorf("fetching custom field choices: %s", err)
+	}
+
+	for name, ids := range choices {
+		definitions.Fields[name] = customField{Type: "select", ChoiceIDs: ids}
+	}
+
+	return definitions, nil
+}
+
+// expandCustomFields returns the custom fields to send to Netbox, or nil when
+// there is nothing to change. Selection fields accept the label of a choice,
+// or its ID before Netbox 2.10, and boolean fields "true" or "false"; every
+// other value is sent as a string, which Netbox converts for integer, date
+// and URL fields. Netbox only reports which fields are boolean since 2.10,
+// fields of unknown type are sent as strings. Fields removed from the configuration are cleared, fields
+// never configured are left out so Netbox keeps their value.
+func expandCustomFields(d *schema.ResourceData, meta interface{}) (interface{}, error) {
+	o, _ := d.GetChange("custom_fields")
+	old := o.(map[string]interface{})
+	configured := d.Get("custom_fields").(map[string]interface{})
+
+	if len(old) == 0 && len(configured) == 0 {
+		return nil, nil
+	}
+
+	fields := map[string]interface{}{}
+
+	for name := range old {
+		if _, ok := configured[name]; !ok {
+			fields[name] = nil
+		}
+	}
+
+	definitions := &customFieldDefinitions{}
+
+	if len(configured) > 0 {
+		var err error
+		if definitions, err = meta.(*ProviderNetboxClient).customFieldDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, raw := range configured {
+		value := raw.(string)
+		field := definitions.Fields[name]
+
+		if value == "" {
+			fields[name] = value
+			continue
+		}
+
+		switch {
+		case field.ChoiceIDs != nil:
+			id, err := selectionChoiceID(name, field.ChoiceIDs, value)
+			if err != nil {
+				return nil, err
+			}
+			fields[name] = id
+		case field.Type == "select":
+			if err := selectionChoice(name, field.Choices, value); err != nil {
+				return nil, err
+			}
+			fields[name] = value
+		case field.Type == "boolean":
+			switch value {
+			case "true":
+				fields[name] = true
+			case "false":
+				fields[name] = false
+			default:
+				fields[name] = value
+			}
+		default:
+			fields[name] = value
+		}
+	}
+
+	return fields, nil
+}
+
+// selectionChoiceID resolves the label or ID of a selection field choice.
+func selectionChoiceID(name string, choices map[string]int64, value string) (int64, error) {
+	if id, ok := choices[value]; ok {
+		return id, nil
+	}
+
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		for _, choice := range choices {
+			if choice == id {
+				return id, nil
+			}
+		}
+	}
+
+	var labels []string
+	for label := range choices {
+		labels = append(labels, label)
+	}
+
+	return 0, unknownChoiceError(name, value, labels)
+}
+
+// selectionChoice checks the value of a selection field against its choices.
+func selectionChoice(name string, choices []string, value string) error {
+	for _, choice := range choices {
+		if choice == value {
+			return nil
+		}
+	}
+
+	return unknownChoiceError(name, value, append([]string(nil), choices...))
+}
+
+func unknownChoiceError(name, value string, choices []string) error {
+	sort.Strings(choices)
+
+	return fmt.Errorf("custom field %q has no choice %q, expected one of: %s", name, value, strings.Join(choices, ", "))
+}
+
+// flattenCustomFields returns the value of the custom_fields attribute for the
+// custom fields read from Netbox. Only the configured fields are tracked, so
+// fields managed outside of Terraform don't show up as drift.
+func flattenCustomFields(d *schema.ResourceData, customFields interface{}) map[string]interface{} {
+	configured := d.Get("custom_fields").(map[string]interface{})
+	values, _ := customFields.(map[string]interface{})
+
+	result := map[string]interface{}{}
+
+	for name, want := range configured {
+		value, ok := values[name]
+		if !ok || value == nil {
+			// Netbox reports cleared fields as null.
+			if want == "" {
+				result[name] = ""
+			}
+			continue
+		}
+
+		if v, ok := value.(map[string]interface{}); ok {
+			// Selection fields read back as {"value": id, "label": label}, keep
+			// whichever form is configured.
+			if id := customFieldString(v["value"]); want == id {
+				result[name] = id
+			} else {
+				result[name] = customFieldString(v["label"])
+			}
+			continue
+		}
+
+		result[name] = customFieldString(value)
+	}
+
+	return result
+}
+
+// customFieldString formats a custom field value decoded from JSON.
+func customFieldString(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// listedCustomFields returns every custom field set on a listed object as a
+// string, selection fields by label.
+func listedCustomFields(customFields interface{}) map[string]interface{} {
+	values, _ := customFields.(map[string]interface{})
+
+	result := map[string]interface{}{}
+	for name, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			result[name] = customFieldString(v["label"])
+		default:
+			result[name] = customFieldString(v)
+		}
+	}
+
+	return result
+}
+
+// customFieldsMatch reports whether the custom fields of a listed object, as
+// returned by listedCustomFields, match every regular expression of a
+// custom_field_filter attribute. Unset fields match nothing.
+func customFieldsMatch(filter map[string]interface{}, customFields map[string]interface{}) (bool, error) {
+	for name, pattern := range filter {
+		re, err := regexp.Compile(pattern.(string))
+		if err != nil {
+			return false, err
+		}
+		value, ok := customFields[name]
+		if !ok || !re.MatchString(value.(string)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
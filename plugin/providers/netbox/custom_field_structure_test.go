@@ -0,0 +1,245 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const testCustomFieldChoices = `{"monitoring": {"None": 1, "Basic": 2, "Full": 3}}`
+
+func TestResourceNetboxIpamPrefix_customFields(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/extras/_custom_field_choices/": {body: testCustomFieldChoices},
+		"POST /api/ipam/prefixes/":               {status: http.StatusCreated, body: `{"id": 42, "prefix": "10.0.0.0/24"}`},
+		"GET /api/ipam/prefixes/42/": {body: `{"id": 42, "prefix": "10.0.0.0/24", "custom_fields": {
+			"owner": "alice",
+			"ticket": 1234,
+			"backup": true,
+			"commissioned": "2019-06-01",
+			"runbook": "https://wiki.example.com/runbook",
+			"monitoring": {"value": 3, "label": "Full"},
+			"unmanaged": "set-in-ui"
+		}}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	configured := map[string]interface{}{
+		"owner":        "alice",
+		"ticket":       "1234",
+		"backup":       "true",
+		"commissioned": "2019-06-01",
+		"runbook":      "https://wiki.example.com/runbook",
+		"monitoring":   "Full",
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamPrefix().Schema, map[string]interface{}{
+		"prefix":        "10.0.0.0/24",
+		"custom_fields": configured,
+	})
+
+	if err := resourceNetboxIpamPrefixCreate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var sent struct {
+		CustomFields map[string]interface{} `json:"custom_fields"`
+	}
+	requests := server.received()
+	if err := json.Unmarshal([]byte(requests[len(requests)-1].body), &sent); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"owner":        "alice",
+		"ticket":       "1234",
+		"backup":       "true",
+		"commissioned": "2019-06-01",
+		"runbook":      "https://wiki.example.com/runbook",
+		"monitoring":   float64(3),
+	}
+	if !reflect.DeepEqual(sent.CustomFields, expected) {
+		t.Fatalf("expected %v to be sent, got %v", expected, sent.CustomFields)
+	}
+
+	if err := resourceNetboxIpamPrefixRead(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := d.Get("custom_fields").(map[string]interface{}); !reflect.DeepEqual(actual, configured) {
+		t.Fatalf("expected %v in state, got %v", configured, actual)
+	}
+}
+
+func TestResourceNetboxIpamPrefix_customFieldsDefinitions(t *testing.T) {
+	server := newTestNetboxServer(t, "2.10", map[string]testRoute{
+		"GET /api/extras/custom-fields/?limit=1000&offset=0": {body: testFixture(t, "api", "2.10", "custom-fields.json")},
+		"POST /api/ipam/prefixes/":                           {status: http.StatusCreated, body: `{"id": 42, "prefix": "10.0.0.0/24"}`},
+		"GET /api/ipam/prefixes/42/": {body: `{"id": 42, "prefix": "10.0.0.0/24", "custom_fields": {
+			"owner": "true",
+			"ticket": 1234,
+			"backup": false,
+			"monitoring": "Full"
+		}}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	configured := map[string]interface{}{
+		"owner":      "true",
+		"ticket":     "1234",
+		"backup":     "false",
+		"monitoring": "Full",
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamPrefix().Schema, map[string]interface{}{
+		"prefix":        "10.0.0.0/24",
+		"custom_fields": configured,
+	})
+
+	if err := resourceNetboxIpamPrefixCreate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var sent struct {
+		CustomFields map[string]interface{} `json:"custom_fields"`
+	}
+	requests := server.received()
+	if err := json.Unmarshal([]byte(requests[len(requests)-1].body), &sent); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Text fields keep "true" as a string and selection fields take the
+	// value of the choice.
+	expected := map[string]interface{}{
+		"owner":      "true",
+		"ticket":     "1234",
+		"backup":     false,
+		"monitoring": "Full",
+	}
+	if !reflect.DeepEqual(sent.CustomFields, expected) {
+		t.Fatalf("expected %v to be sent, got %v", expected, sent.CustomFields)
+	}
+
+	if err := resourceNetboxIpamPrefixRead(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if actual := d.Get("custom_fields").(map[string]interface{}); !reflect.DeepEqual(actual, configured) {
+		t.Fatalf("expected %v in state, got %v", configured, actual)
+	}
+}
+
+func TestExpandCustomFields_removed(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/extras/_custom_field_choices/": {body: testCustomFieldChoices},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	d := resourceNetboxIpamPrefix().TestResourceData()
+	d.SetId("ipam/prefix/42")
+	d.Set("custom_fields", map[string]interface{}{"owner": "alice", "ticket": "1234"})
+
+	d = resourceNetboxIpamPrefix().Data(d.State())
+	d.Set("custom_fields", map[string]interface{}{"owner": "bob"})
+
+	fields, err := expandCustomFields(d, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{"owner": "bob", "ticket": nil}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestExpandCustomFields_invalidChoice(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/extras/_custom_field_choices/": {body: testCustomFieldChoices},
+	})
+	defer server.Close()
+
+	d := resourceNetboxIpamPrefix().TestResourceData()
+	d.Set("custom_fields", map[string]interface{}{"monitoring": "Partial"})
+
+	_, err := expandCustomFields(d, server.meta(t))
+	if err == nil || !strings.Contains(err.Error(), "Basic, Full, None") {
+		t.Fatalf("expected an error listing the choices, got %v", err)
+	}
+}
+
+func TestExpandCustomFields_untyped(t *testing.T) {
+	// Before 2.10 only selection fields are known, a text field holding
+	// "true" must stay a string.
+	server := newTestNetboxServer(t, "2.9", map[string]testRoute{
+		"GET /api/extras/_custom_field_choices/": {body: testCustomFieldChoices},
+	})
+	defer server.Close()
+
+	d := resourceNetboxIpamPrefix().TestResourceData()
+	d.Set("custom_fields", map[string]interface{}{"owner": "true", "monitoring": "Full"})
+
+	fields, err := expandCustomFields(d, server.meta(t))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{"owner": "true", "monitoring": int64(3)}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestExpandCustomFields_invalidChoiceDefinitions(t *testing.T) {
+	server := newTestNetboxServer(t, "2.10", map[string]testRoute{
+		"GET /api/extras/custom-fields/?limit=1000&offset=0": {body: testFixture(t, "api", "2.10", "custom-fields.json")},
+	})
+	defer server.Close()
+
+	d := resourceNetboxIpamPrefix().TestResourceData()
+	d.Set("custom_fields", map[string]interface{}{"monitoring": "3"})
+
+	_, err := expandCustomFields(d, server.meta(t))
+	if err == nil || !strings.Contains(err.Error(), "Basic, Full, None") {
+		t.Fatalf("expected an error listing the choices, got %v", err)
+	}
+}
+
+func TestExpandCustomFields_noChoices(t *testing.T) {
+	// Servers without selection custom fields may not serve the choices.
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{})
+	defer server.Close()
+
+	d := resourceNetboxIpamPrefix().TestResourceData()
+	d.Set("custom_fields", map[string]interface{}{"owner": "alice", "monitoring": "Full"})
+
+	fields, err := expandCustomFields(d, server.meta(t))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{"owner": "alice", "monitoring": "Full"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestExpandCustomFields_unset(t *testing.T) {
+	d := resourceNetboxIpamPrefix().TestResourceData()
+
+	fields, err := expandCustomFields(d, &ProviderNetboxClient{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fields != nil {
+		t.Fatalf("expected custom fields to be left out, got %v", fields)
+	}
+}
@@ -0,0 +1,130 @@
+package netbox
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// The list data sources, such as netbox_ipam_prefixes, return every object
+// matching their filters as a list of nested blocks. Results are paged
+// through with doJSONList, the custom field filter is applied to them
+// afterwards as Netbox cannot match custom fields by regular expression.
+
+// listFilterSchema returns the filters shared by every list data source, to
+// which each adds its own filters and its list of results.
+func listFilterSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"query": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Free text search, as in the Netbox web interface.",
+		},
+		"tenant": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name or slug of the tenant.",
+		},
+		"tags": &schema.Schema{
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+			Description: "Slugs of tags the objects must all have.",
+		},
+		"custom_field_filter": customFieldFilterSchema(nil),
+	}
+}
+
+// listFilterQuery returns the query of the filters shared by every list data
+// source.
+func listFilterQuery(d *schema.ResourceData) url.Values {
+	query := url.Values{}
+
+	if q := d.Get("query").(string); q != "" {
+		query.Set("q", q)
+	}
+	if tenant := d.Get("tenant").(string); tenant != "" {
+		query.Set("tenant", dataSourceNetboxPrefixAttrPrep(tenant))
+	}
+
+	var tags []string
+	for _, tag := range d.Get("tags").(*schema.Set).List() {
+		tags = append(tags, tag.(string))
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+
+	return query
+}
+
+// listDataSourceID returns the ID of a list data source, derived from its
+// query and custom field filter, when it has one, so that it changes with
+// them: "ipam/prefixes/<hash>".
+func listDataSourceID(d *schema.ResourceData, kind string, query url.Values) string {
+	key := url.Values{}
+	for name, values := range query {
+		key[name] = values
+	}
+	filter, _ := d.Get("custom_field_filter").(map[string]interface{})
+	for name, pattern := range filter {
+		key.Set("cf_"+name, pattern.(string))
+	}
+
+	return fmt.Sprintf("%s/%d", kind, hashcode.String(key.Encode()))
+}
+
+// nestedObject is a related object nested in a listed one, of which only the
+// ID is kept. It is left zero when the relation is null.
+type nestedObject struct {
+	ID int64 `json:"id"`
+}
+
+// cidrFamily returns the address family, 4 or 6, of an address or prefix in
+// CIDR notation.
+func cidrFamily(cidr string) int {
+	ip, _, err := net.ParseCIDR(cidr)
+	switch {
+	case err != nil:
+		return 0
+	case ip.To4() != nil:
+		return 4
+	}
+	return 6
+}
+
+// listedTags returns the tags of a listed object as the value of its tags
+// attribute.
+func listedTags(tags []string) []interface{} {
+	result := []interface{}{}
+	for _, tag := range tags {
+		result = append(result, tag)
+	}
+	return result
+}
+
+// listedTagsSchema returns the schema of the tags of a listed object.
+func listedTagsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Set:      schema.HashString,
+	}
+}
+
+// listedCustomFieldsSchema returns the schema of the custom fields of a listed
+// object.
+func listedCustomFieldsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
@@ -2,7 +2,7 @@ package netbox
 
 import (
 	"errors"
-	//"fmt"
+	"fmt"
 	"log"
 	"strconv"
   "strings"
@@ -22,7 +22,7 @@ func dataSourceNetboxIPAddress() *schema.Resource {
 }
 
 func dataSourceNetboxIPAddressParse(d *schema.ResourceData, obj *models.IPAddress) {
-  d.SetId(strconv.FormatInt(obj.ID, 10))
+  d.SetId(fmt.Sprintf("ipam/ip-address/%d", obj.ID))
   d.Set("created", obj.Created.String())
   d.Set("description", obj.Description)
   d.Set("status", *obj.Status.Label)
@@ -82,7 +82,11 @@ func dataSourceNetboxIPAddressesRead(d *schema.ResourceData, meta interface{}) e
     }
 
     if family, familyOk := d.GetOk("family"); familyOk {
-      family_str := family.(string)
+      value, err := familyChoices.parse(family.(string))
+      if err != nil {
+        return err
+      }
+      family_str := strconv.FormatInt(value, 10)
       param.SetFamily(&family_str)
     }
 
@@ -150,6 +154,7 @@ func bareIPAddressesSchema() map[string]*schema.Schema {
 		"family": &schema.Schema{
 			Type: schema.TypeString,
       Optional: true,
+      ValidateFunc: familyChoices.validate,
 		},
 		"vrf": &schema.Schema{
 			Type: schema.TypeString,
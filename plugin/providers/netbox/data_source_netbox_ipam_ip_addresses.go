@@ -0,0 +1,193 @@
+package netbox
+
+import (
+	"encoding/json"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceNetboxIpamIPAddresses lists every IP address matching its
+// filters.
+func dataSourceNetboxIpamIPAddresses() *schema.Resource {
+	s := listFilterSchema()
+
+	s["parent"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateCIDRPrefix,
+		Description:  "Only list the addresses within this prefix, such as 10.0.0.0/24.",
+	}
+	s["family"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: familyChoices.validate,
+	}
+	s["role"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: ipAddressRoleChoices.validate,
+		Description:  "Role of the addresses, such as vip or 40.",
+	}
+	s["ip_addresses"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ip_address_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"address": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"family": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"status": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"role": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"vrf_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"tenant_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"interface_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"nat_inside_ip_address_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"dns_name": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"description": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"tags":          listedTagsSchema(),
+				"custom_fields": listedCustomFieldsSchema(),
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceNetboxIpamIPAddressesRead,
+		Schema: s,
+	}
+}
+
+// ipAddressListPayload is an IP address as listed by Netbox.
+type ipAddressListPayload struct {
+	ID           int64                   `json:"id"`
+	Address      string                  `json:"address"`
+	Status       *models.IPAddressStatus `json:"status"`
+	Role         *models.IPAddressRole   `json:"role"`
+	Vrf          nestedObject            `json:"vrf"`
+	Tenant       nestedObject            `json:"tenant"`
+	Interface    nestedObject            `json:"interface"`
+	NatInside    nestedObject            `json:"nat_inside"`
+	DNSName      string                  `json:"dns_name"`
+	Description  string                  `json:"description"`
+	Tags         []string                `json:"tags"`
+	CustomFields interface{}             `json:"custom_fields"`
+}
+
+// dataSourceNetboxIpamIPAddressesRead fetches every IP address matching the
+// filters, in the order Netbox returns them.
+func dataSourceNetboxIpamIPAddressesRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	query := listFilterQuery(d)
+
+	if parent := d.Get("parent").(string); parent != "" {
+		query.Set("parent", parent)
+	}
+	if family := d.Get("family").(string); family != "" {
+		value, err := familyChoices.parse(family)
+		if err != nil {
+			return err
+		}
+		query.Set("family", strconv.FormatInt(value, 10))
+	}
+	if role := d.Get("role").(string); role != "" {
+		value, err := ipAddressRoleChoices.filter(role, c.apiVersion())
+		if err != nil {
+			return err
+		}
+		query.Set("role", value)
+	}
+
+	log.Debugf("Listing the IP addresses matching %v", query)
+
+	results, err := c.doJSONList("/ipam/ip-addresses/", query)
+	if err != nil {
+		return err
+	}
+
+	filter := d.Get("custom_field_filter").(map[string]interface{})
+
+	addresses := []interface{}{}
+	for _, raw := range results {
+		var address ipAddressListPayload
+		if err := json.Unmarshal(raw, &address); err != nil {
+			return err
+		}
+
+		customFields := listedCustomFields(address.CustomFields)
+		ok, err := customFieldsMatch(filter, customFields)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		var status, role string
+		if address.Status != nil && address.Status.Value != nil {
+			status, _ = ipAddressStatusChoices.slug(*address.Status.Value)
+		}
+		if address.Role != nil && address.Role.Value != nil {
+			role, _ = ipAddressRoleChoices.slug(*address.Role.Value)
+		}
+
+		addresses = append(addresses, map[string]interface{}{
+			"ip_address_id":            int(address.ID),
+			"address":                  address.Address,
+			"family":                   cidrFamily(address.Address),
+			"status":                   status,
+			"role":                     role,
+			"vrf_id":                   int(address.Vrf.ID),
+			"tenant_id":                int(address.Tenant.ID),
+			"interface_id":             int(address.Interface.ID),
+			"nat_inside_ip_address_id": int(address.NatInside.ID),
+			"dns_name":                 address.DNSName,
+			"description":              address.Description,
+			"tags":                     listedTags(address.Tags),
+			"custom_fields":            customFields,
+		})
+	}
+
+	d.SetId(listDataSourceID(d, "ipam/ip-addresses", query))
+	if err := d.Set("ip_addresses", addresses); err != nil {
+		return err
+	}
+
+	return nil
+}
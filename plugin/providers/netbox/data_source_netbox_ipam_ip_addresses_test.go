@@ -0,0 +1,98 @@
+package netbox
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceNetboxIpamIPAddresses_read(t *testing.T) {
+	cases := []struct {
+		version string
+		route   string
+		body    string
+	}{
+		{
+			version: "2.5",
+			route:   "GET /api/ipam/ip-addresses/?family=6&limit=1000&offset=0&parent=2001%3Adb8%3A%3A%2F64&role=40",
+			body: `{"count": 1, "results": [
+				{"id": 10, "address": "2001:db8::1/64", "status": {"value": 1, "label": "Active"}, "role": {"value": 40, "label": "VIP"},
+				 "vrf": {"id": 3}, "tenant": null, "interface": {"id": 7, "name": "eth0"}, "nat_inside": {"id": 11},
+				 "description": "Router", "tags": ["managed"], "custom_fields": {}}
+			]}`,
+		},
+		{
+			// An undetected version is spoken to in the client dialect.
+			version: "",
+			route:   "GET /api/ipam/ip-addresses/?family=6&limit=1000&offset=0&parent=2001%3Adb8%3A%3A%2F64&role=40",
+			body: `{"count": 1, "results": [
+				{"id": 10, "address": "2001:db8::1/64", "status": {"value": 1, "label": "Active"}, "role": {"value": 40, "label": "VIP"},
+				 "vrf": {"id": 3}, "tenant": null, "interface": {"id": 7, "name": "eth0"}, "nat_inside": {"id": 11},
+				 "description": "Router", "tags": ["managed"], "custom_fields": {}}
+			]}`,
+		},
+		{
+			version: "2.6",
+			route:   "GET /api/ipam/ip-addresses/?family=6&limit=1000&offset=0&parent=2001%3Adb8%3A%3A%2F64&role=40",
+			body: `{"count": 1, "results": [
+				{"id": 10, "address": "2001:db8::1/64", "status": {"value": 1, "label": "Active"}, "role": {"value": 40, "label": "VIP"},
+				 "vrf": {"id": 3}, "tenant": null, "interface": {"id": 7, "name": "eth0"}, "nat_inside": {"id": 11},
+				 "dns_name": "router.example.com", "description": "Router", "tags": ["managed"], "custom_fields": {}}
+			]}`,
+		},
+		{
+			version: "2.9",
+			route:   "GET /api/ipam/ip-addresses/?family=6&limit=1000&offset=0&parent=2001%3Adb8%3A%3A%2F64&role=vip",
+			body: `{"count": 1, "results": [
+				{"id": 10, "address": "2001:db8::1/64", "status": {"value": "active", "label": "Active"}, "role": {"value": "vip", "label": "VIP"},
+				 "vrf": {"id": 3}, "tenant": null, "assigned_object_type": "dcim.interface", "assigned_object_id": 7,
+				 "assigned_object": {"id": 7, "name": "eth0", "device": {"id": 3, "name": "router"}}, "nat_inside": {"id": 11},
+				 "dns_name": "router.example.com", "description": "Router", "tags": [{"name": "managed", "slug": "managed"}], "custom_fields": {}}
+			]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		server := newTestNetboxServer(t, tc.version, map[string]testRoute{tc.route: {body: tc.body}})
+
+		d := schema.TestResourceDataRaw(t, dataSourceNetboxIpamIPAddresses().Schema, map[string]interface{}{
+			"parent": "2001:db8::/64",
+			"family": "6",
+			"role":   "vip",
+		})
+
+		if err := dataSourceNetboxIpamIPAddressesRead(d, server.meta(t)); err != nil {
+			t.Fatalf("%s: err: %s", tc.version, err)
+		}
+
+		addresses := d.Get("ip_addresses").([]interface{})
+		if len(addresses) != 1 {
+			t.Fatalf("%s: expected one address, got %v", tc.version, addresses)
+		}
+		address := addresses[0].(map[string]interface{})
+
+		expected := map[string]interface{}{
+			"ip_address_id":            10,
+			"address":                  "2001:db8::1/64",
+			"family":                   6,
+			"status":                   "active",
+			"role":                     "vip",
+			"vrf_id":                   3,
+			"tenant_id":                0,
+			"interface_id":             7,
+			"nat_inside_ip_address_id": 11,
+			"description":              "Router",
+		}
+		for name, value := range expected {
+			if actual := address[name]; actual != value {
+				t.Errorf("%s: expected %s to be %v, got %v", tc.version, name, value, actual)
+			}
+		}
+		if tags := address["tags"].(*schema.Set).List(); !reflect.DeepEqual(tags, []interface{}{"managed"}) {
+			t.Errorf("%s: unexpected tags %v", tc.version, tags)
+		}
+
+		server.Close()
+	}
+}
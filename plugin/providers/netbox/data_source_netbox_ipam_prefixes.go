@@ -0,0 +1,201 @@
+package netbox
+
+import (
+	"encoding/json"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// dataSourceNetboxIpamPrefixes lists every prefix matching its filters.
+func dataSourceNetboxIpamPrefixes() *schema.Resource {
+	s := listFilterSchema()
+
+	s["within"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateCIDRPrefix,
+		Description:  "Only list the prefixes within this one, such as 10.0.0.0/8.",
+	}
+	s["family"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: familyChoices.validate,
+	}
+	s["site"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Name or slug of the site.",
+	}
+	s["role"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Name or slug of the prefix and VLAN role.",
+	}
+	s["vlan_vid"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntBetween(1, 4094),
+	}
+	s["prefixes"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"prefix_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"prefix": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"family": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"status": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"vrf_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"tenant_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"site_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"vlan_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"role_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"is_pool": &schema.Schema{
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+				"description": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"tags":          listedTagsSchema(),
+				"custom_fields": listedCustomFieldsSchema(),
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceNetboxIpamPrefixesRead,
+		Schema: s,
+	}
+}
+
+// prefixListPayload is a prefix as listed by Netbox.
+type prefixListPayload struct {
+	ID           int64                `json:"id"`
+	Prefix       string               `json:"prefix"`
+	Status       *models.PrefixStatus `json:"status"`
+	Vrf          nestedObject         `json:"vrf"`
+	Tenant       nestedObject         `json:"tenant"`
+	Site         nestedObject         `json:"site"`
+	Vlan         nestedObject         `json:"vlan"`
+	Role         nestedObject         `json:"role"`
+	IsPool       bool                 `json:"is_pool"`
+	Description  string               `json:"description"`
+	Tags         []string             `json:"tags"`
+	CustomFields interface{}          `json:"custom_fields"`
+}
+
+// dataSourceNetboxIpamPrefixesRead fetches every prefix matching the filters,
+// in the order Netbox returns them.
+func dataSourceNetboxIpamPrefixesRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	query := listFilterQuery(d)
+
+	if within := d.Get("within").(string); within != "" {
+		query.Set("within", within)
+	}
+	if family := d.Get("family").(string); family != "" {
+		value, err := familyChoices.parse(family)
+		if err != nil {
+			return err
+		}
+		query.Set("family", strconv.FormatInt(value, 10))
+	}
+	if site := d.Get("site").(string); site != "" {
+		query.Set("site", dataSourceNetboxPrefixAttrPrep(site))
+	}
+	if role := d.Get("role").(string); role != "" {
+		query.Set("role", dataSourceNetboxPrefixAttrPrep(role))
+	}
+	if vid := d.Get("vlan_vid").(int); vid != 0 {
+		query.Set("vlan_vid", strconv.Itoa(vid))
+	}
+
+	log.Debugf("Listing the prefixes matching %v", query)
+
+	results, err := c.doJSONList("/ipam/prefixes/", query)
+	if err != nil {
+		return err
+	}
+
+	filter := d.Get("custom_field_filter").(map[string]interface{})
+
+	prefixes := []interface{}{}
+	for _, raw := range results {
+		var prefix prefixListPayload
+		if err := json.Unmarshal(raw, &prefix); err != nil {
+			return err
+		}
+
+		customFields := listedCustomFields(prefix.CustomFields)
+		ok, err := customFieldsMatch(filter, customFields)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		var status string
+		if prefix.Status != nil && prefix.Status.Value != nil {
+			status, _ = prefixStatusChoices.slug(*prefix.Status.Value)
+		}
+
+		prefixes = append(prefixes, map[string]interface{}{
+			"prefix_id":     int(prefix.ID),
+			"prefix":        prefix.Prefix,
+			"family":        cidrFamily(prefix.Prefix),
+			"status":        status,
+			"vrf_id":        int(prefix.Vrf.ID),
+			"tenant_id":     int(prefix.Tenant.ID),
+			"site_id":       int(prefix.Site.ID),
+			"vlan_id":       int(prefix.Vlan.ID),
+			"role_id":       int(prefix.Role.ID),
+			"is_pool":       prefix.IsPool,
+			"description":   prefix.Description,
+			"tags":          listedTags(prefix.Tags),
+			"custom_fields": customFields,
+		})
+	}
+
+	d.SetId(listDataSourceID(d, "ipam/prefixes", query))
+	if err := d.Set("prefixes", prefixes); err != nil {
+		return err
+	}
+
+	return nil
+}
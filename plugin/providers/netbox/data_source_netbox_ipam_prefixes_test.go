@@ -0,0 +1,138 @@
+package netbox
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceNetboxIpamPrefixes_read(t *testing.T) {
+	const query = "family=4&limit=1000&offset=%d&q=west&role=production&site=inkopolis&tag=managed&tag=web&tenant=toni-kensa-gmbh&vlan_vid=16&within=192.168.0.0%2F16"
+
+	server := newTestNetboxServer(t, "2.9", map[string]testRoute{
+		"GET /api/ipam/prefixes/?" + strings.Replace(query, "%d", "0", 1): {body: `{"count": 3, "results": [
+			{"id": 1, "prefix": "192.168.100.0/24", "status": {"value": "active", "label": "Active"},
+			 "vrf": {"id": 3}, "tenant": {"id": 7}, "site": {"id": 2}, "vlan": {"id": 9}, "role": {"id": 1},
+			 "is_pool": true, "description": "Primary", "tags": [{"name": "managed", "slug": "managed"}, {"name": "web", "slug": "web"}],
+			 "custom_fields": {"owner": "network team", "tier": {"value": 2, "label": "Gold"}}},
+			{"id": 2, "prefix": "192.168.101.0/24", "status": {"value": "reserved", "label": "Reserved"},
+			 "vrf": null, "tenant": null, "site": null, "vlan": null, "role": null,
+			 "tags": [{"name": "managed", "slug": "managed"}, {"name": "web", "slug": "web"}],
+			 "custom_fields": {"owner": "network team", "tier": null}}
+		]}`},
+		// Netbox may return less than a full page.
+		"GET /api/ipam/prefixes/?" + strings.Replace(query, "%d", "2", 1): {body: `{"count": 3, "results": [
+			{"id": 3, "prefix": "192.168.102.0/24", "status": {"value": "active", "label": "Active"},
+			 "tags": [{"name": "managed", "slug": "managed"}, {"name": "web", "slug": "web"}],
+			 "custom_fields": {"owner": "application team"}}
+		]}`},
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceNetboxIpamPrefixes().Schema, map[string]interface{}{
+		"query":               "west",
+		"within":              "192.168.0.0/16",
+		"family":              "IPv4",
+		"tenant":              "Toni Kensa GmbH",
+		"site":                "Inkopolis",
+		"role":                "production",
+		"vlan_vid":            16,
+		"tags":                []interface{}{"web", "managed"},
+		"custom_field_filter": map[string]interface{}{"owner": "^network"},
+	})
+
+	if err := dataSourceNetboxIpamPrefixesRead(d, server.meta(t)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []map[string]interface{}{
+		{
+			"prefix_id":   1,
+			"prefix":      "192.168.100.0/24",
+			"family":      4,
+			"status":      "active",
+			"vrf_id":      3,
+			"tenant_id":   7,
+			"site_id":     2,
+			"vlan_id":     9,
+			"role_id":     1,
+			"is_pool":     true,
+			"description": "Primary",
+		},
+		{
+			"prefix_id":   2,
+			"prefix":      "192.168.101.0/24",
+			"family":      4,
+			"status":      "reserved",
+			"vrf_id":      0,
+			"tenant_id":   0,
+			"site_id":     0,
+			"vlan_id":     0,
+			"role_id":     0,
+			"is_pool":     false,
+			"description": "",
+		},
+	}
+
+	prefixes := d.Get("prefixes").([]interface{})
+	if len(prefixes) != len(expected) {
+		t.Fatalf("expected %d prefixes, got %#v", len(expected), prefixes)
+	}
+	for i, values := range expected {
+		prefix := prefixes[i].(map[string]interface{})
+		for name, value := range values {
+			if actual := prefix[name]; actual != value {
+				t.Errorf("prefix %d: expected %s to be %v, got %v", i, name, value, actual)
+			}
+		}
+		if tags := prefix["tags"].(*schema.Set); tags.Len() != 2 || !tags.Contains("managed") || !tags.Contains("web") {
+			t.Errorf("prefix %d: unexpected tags %v", i, tags.List())
+		}
+	}
+
+	customFields := []map[string]interface{}{
+		{"owner": "network team", "tier": "Gold"},
+		{"owner": "network team"},
+	}
+	for i, values := range customFields {
+		if actual := prefixes[i].(map[string]interface{})["custom_fields"]; !reflect.DeepEqual(actual, values) {
+			t.Errorf("prefix %d: expected custom fields %v, got %v", i, values, actual)
+		}
+	}
+
+	if !strings.HasPrefix(d.Id(), "ipam/prefixes/") {
+		t.Fatalf("unexpected ID %q", d.Id())
+	}
+}
+
+func TestDataSourceNetboxIpamPrefixes_id(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/prefixes/?limit=1000&offset=0&q=west":            {body: `{"count": 0, "results": []}`},
+		"GET /api/ipam/prefixes/?limit=1000&offset=0&q=east":            {body: `{"count": 0, "results": []}`},
+		"GET /api/ipam/prefixes/?limit=1000&offset=0&tenant=toni-kensa": {body: `{"count": 0, "results": []}`},
+	})
+	defer server.Close()
+
+	ids := map[string]bool{}
+	for _, raw := range []map[string]interface{}{
+		{"query": "west"},
+		{"query": "east"},
+		{"tenant": "Toni Kensa"},
+		{"query": "west", "custom_field_filter": map[string]interface{}{"owner": "network"}},
+	} {
+		d := schema.TestResourceDataRaw(t, dataSourceNetboxIpamPrefixes().Schema, raw)
+
+		if err := dataSourceNetboxIpamPrefixesRead(d, server.meta(t)); err != nil {
+			t.Fatalf("%v: err: %s", raw, err)
+		}
+		if prefixes := d.Get("prefixes").([]interface{}); len(prefixes) != 0 {
+			t.Fatalf("%v: expected no prefix, got %v", raw, prefixes)
+		}
+		if ids[d.Id()] {
+			t.Fatalf("%v: ID %q is not unique to the filters", raw, d.Id())
+		}
+		ids[d.Id()] = true
+	}
+}
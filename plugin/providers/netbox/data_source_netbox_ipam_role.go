@@ -0,0 +1,87 @@
+package netbox
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceNetboxIpamRole looks up a prefix and VLAN role by name or slug.
+func dataSourceNetboxIpamRole() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetboxIpamRoleRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"role_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"weight": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceNetboxIpamRoleRead finds the single role matching the name
+// and/or slug.
+func dataSourceNetboxIpamRoleRead(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	name, nameOk := d.GetOk("name")
+	slug, slugOk := d.GetOk("slug")
+
+	if !nameOk && !slugOk {
+		return fmt.Errorf("one of name or slug must be set")
+	}
+
+	param := ipam.NewIPAMRolesListParams()
+	if nameOk {
+		name := name.(string)
+		param.SetName(&name)
+	}
+	if slugOk {
+		slug := slug.(string)
+		param.SetSlug(&slug)
+	}
+
+	limit := int64(2)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing IPAMRolesList against Netbox: %v", param)
+
+	out, err := netboxClient.IPAM.IPAMRolesList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMRolesList: %v", err)
+
+		return err
+	}
+
+	switch {
+	case len(out.Payload.Results) == 0:
+		return fmt.Errorf("no role matches name %q and slug %q", name, slug)
+	case len(out.Payload.Results) > 1:
+		return fmt.Errorf("more than one role matches name %q and slug %q", name, slug)
+	}
+
+	role := out.Payload.Results[0]
+
+	d.SetId(fmt.Sprintf("ipam/role/%d", role.ID))
+
+	return setIpamRole(d, role)
+}
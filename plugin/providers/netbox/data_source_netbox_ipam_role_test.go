@@ -0,0 +1,54 @@
+package netbox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceNetboxIpamRole_read(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/roles/?limit=2&name=Production": {body: `{"count": 1, "results": [{"id": 4, "name": "Production", "slug": "production", "weight": 100}]}`},
+		"GET /api/ipam/roles/?limit=2&slug=production": {body: `{"count": 1, "results": [{"id": 4, "name": "Production", "slug": "production", "weight": 100}]}`},
+		"GET /api/ipam/roles/?limit=2&slug=missing":    {body: `{"count": 0, "results": []}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for _, raw := range []map[string]interface{}{
+		{"name": "Production"},
+		{"slug": "production"},
+	} {
+		d := schema.TestResourceDataRaw(t, dataSourceNetboxIpamRole().Schema, raw)
+
+		if err := dataSourceNetboxIpamRoleRead(d, meta); err != nil {
+			t.Fatalf("%v: err: %s", raw, err)
+		}
+
+		expected := map[string]interface{}{
+			"role_id": 4,
+			"name":    "Production",
+			"slug":    "production",
+			"weight":  100,
+		}
+		for name, value := range expected {
+			if actual := d.Get(name); actual != value {
+				t.Errorf("%v: expected %s to be %v, got %v", raw, name, value, actual)
+			}
+		}
+		if d.Id() != "ipam/role/4" {
+			t.Errorf("%v: expected ID ipam/role/4, got %q", raw, d.Id())
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceNetboxIpamRole().Schema, map[string]interface{}{"slug": "missing"})
+	if err := dataSourceNetboxIpamRoleRead(d, meta); err == nil || err.Error() != `no role matches name "" and slug "missing"` {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceNetboxIpamRole().Schema, map[string]interface{}{})
+	if err := dataSourceNetboxIpamRoleRead(d, meta); err == nil || err.Error() != "one of name or slug must be set" {
+		t.Fatalf("expected a missing search term error, got %v", err)
+	}
+}
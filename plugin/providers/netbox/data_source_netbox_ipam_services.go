@@ -0,0 +1,120 @@
+package netbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceNetboxIpamServices lists the services of a device or virtual
+// machine.
+func dataSourceNetboxIpamServices() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetboxIpamServicesRead,
+
+		Schema: map[string]*schema.Schema{
+			"device_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"virtual_machine_id"},
+			},
+			"virtual_machine_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"device_id"},
+			},
+			"services": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service_id": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"protocol": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ports": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"ip_address_ids": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"description": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceNetboxIpamServicesRead fetches every service of the device or
+// virtual machine, in the order Netbox returns them.
+func dataSourceNetboxIpamServicesRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	query := url.Values{}
+
+	switch {
+	case d.Get("device_id").(int) != 0:
+		query.Set("device_id", strconv.Itoa(d.Get("device_id").(int)))
+	case d.Get("virtual_machine_id").(int) != 0:
+		query.Set("virtual_machine_id", strconv.Itoa(d.Get("virtual_machine_id").(int)))
+	default:
+		return fmt.Errorf("one of device_id or virtual_machine_id must be set")
+	}
+
+	log.Debugf("Listing the services matching %v", query)
+
+	results, err := c.doJSONList("/ipam/services/", query)
+	if err != nil {
+		return err
+	}
+
+	services := []interface{}{}
+	for _, raw := range results {
+		var service servicePayload
+		if err := json.Unmarshal(raw, &service); err != nil {
+			return err
+		}
+
+		var protocol string
+		if service.Protocol != nil && service.Protocol.Value != nil {
+			protocol, _ = serviceProtocolChoices.slug(*service.Protocol.Value)
+		}
+
+		services = append(services, map[string]interface{}{
+			"service_id":     int(service.ID),
+			"name":           service.Name,
+			"protocol":       protocol,
+			"ports":          service.ports(),
+			"ip_address_ids": service.ipAddressIDs(),
+			"description":    service.Description,
+		})
+	}
+
+	d.SetId(listDataSourceID(d, "ipam/services", query))
+	if err := d.Set("services", services); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package netbox
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceNetboxIpamServices_read(t *testing.T) {
+	server := newTestNetboxServer(t, "2.7", map[string]testRoute{
+		"GET /api/ipam/services/?device_id=6&limit=1000&offset=0": {body: `{"count": 2, "results": [
+			{"id": 12, "name": "ssh", "protocol": {"value": "tcp", "label": "TCP"}, "port": 22,
+			 "ipaddresses": [{"id": 10, "address": "192.168.100.1/24"}], "description": "Management access"}
+		]}`},
+		"GET /api/ipam/services/?device_id=6&limit=1000&offset=1": {body: `{"count": 2, "results": [
+			{"id": 13, "name": "bgp", "protocol": {"value": "tcp", "label": "TCP"}, "port": 179, "ipaddresses": []}
+		]}`},
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, dataSourceNetboxIpamServices().Schema, map[string]interface{}{
+		"device_id": 6,
+	})
+
+	if err := dataSourceNetboxIpamServicesRead(d, server.meta(t)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"service_id":     12,
+			"name":           "ssh",
+			"protocol":       "tcp",
+			"ports":          []interface{}{22},
+			"ip_address_ids": []interface{}{10},
+			"description":    "Management access",
+		},
+		map[string]interface{}{
+			"service_id":     13,
+			"name":           "bgp",
+			"protocol":       "tcp",
+			"ports":          []interface{}{179},
+			"ip_address_ids": []interface{}{},
+			"description":    "",
+		},
+	}
+	if services := d.Get("services"); !reflect.DeepEqual(services, expected) {
+		t.Fatalf("expected services %#v, got %#v", expected, services)
+	}
+	if !strings.HasPrefix(d.Id(), "ipam/services/") {
+		t.Fatalf("unexpected ID %q", d.Id())
+	}
+}
@@ -0,0 +1,169 @@
+package netbox
+
+import (
+	"encoding/json"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// dataSourceNetboxIpamVlans lists every VLAN matching its filters.
+func dataSourceNetboxIpamVlans() *schema.Resource {
+	s := listFilterSchema()
+
+	s["vid"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntBetween(1, 4094),
+	}
+	s["site"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Name or slug of the site.",
+	}
+	s["role"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Name or slug of the prefix and VLAN role.",
+	}
+	s["vlans"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"vlan_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"vid": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"name": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"status": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"site_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"group_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"tenant_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"role_id": &schema.Schema{
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"description": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"tags":          listedTagsSchema(),
+				"custom_fields": listedCustomFieldsSchema(),
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceNetboxIpamVlansRead,
+		Schema: s,
+	}
+}
+
+// vlanListPayload is a VLAN as listed by Netbox.
+type vlanListPayload struct {
+	ID           int64              `json:"id"`
+	Vid          int64              `json:"vid"`
+	Name         string             `json:"name"`
+	Status       *models.VLANStatus `json:"status"`
+	Site         nestedObject       `json:"site"`
+	Group        nestedObject       `json:"group"`
+	Tenant       nestedObject       `json:"tenant"`
+	Role         nestedObject       `json:"role"`
+	Description  string             `json:"description"`
+	Tags         []string           `json:"tags"`
+	CustomFields interface{}        `json:"custom_fields"`
+}
+
+// dataSourceNetboxIpamVlansRead fetches every VLAN matching the filters, in
+// the order Netbox returns them.
+func dataSourceNetboxIpamVlansRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	query := listFilterQuery(d)
+
+	if vid := d.Get("vid").(int); vid != 0 {
+		query.Set("vid", strconv.Itoa(vid))
+	}
+	if site := d.Get("site").(string); site != "" {
+		query.Set("site", dataSourceNetboxPrefixAttrPrep(site))
+	}
+	if role := d.Get("role").(string); role != "" {
+		query.Set("role", dataSourceNetboxPrefixAttrPrep(role))
+	}
+
+	log.Debugf("Listing the VLANs matching %v", query)
+
+	results, err := c.doJSONList("/ipam/vlans/", query)
+	if err != nil {
+		return err
+	}
+
+	filter := d.Get("custom_field_filter").(map[string]interface{})
+
+	vlans := []interface{}{}
+	for _, raw := range results {
+		var vlan vlanListPayload
+		if err := json.Unmarshal(raw, &vlan); err != nil {
+			return err
+		}
+
+		customFields := listedCustomFields(vlan.CustomFields)
+		ok, err := customFieldsMatch(filter, customFields)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		var status string
+		if vlan.Status != nil && vlan.Status.Value != nil {
+			status, _ = vlanStatusChoices.slug(*vlan.Status.Value)
+		}
+
+		vlans = append(vlans, map[string]interface{}{
+			"vlan_id":       int(vlan.ID),
+			"vid":           int(vlan.Vid),
+			"name":          vlan.Name,
+			"status":        status,
+			"site_id":       int(vlan.Site.ID),
+			"group_id":      int(vlan.Group.ID),
+			"tenant_id":     int(vlan.Tenant.ID),
+			"role_id":       int(vlan.Role.ID),
+			"description":   vlan.Description,
+			"tags":          listedTags(vlan.Tags),
+			"custom_fields": customFields,
+		})
+	}
+
+	d.SetId(listDataSourceID(d, "ipam/vlans", query))
+	if err := d.Set("vlans", vlans); err != nil {
+		return err
+	}
+
+	return nil
+}
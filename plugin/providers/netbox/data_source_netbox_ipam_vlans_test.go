@@ -0,0 +1,55 @@
+package netbox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceNetboxIpamVlans_read(t *testing.T) {
+	server := newTestNetboxServer(t, "2.7", map[string]testRoute{
+		"GET /api/ipam/vlans/?limit=1000&offset=0&site=inkopolis": {body: `{"count": 2, "results": [
+			{"id": 9, "vid": 16, "name": "VLAN-16", "status": {"value": "active", "label": "Active"},
+			 "site": {"id": 2}, "group": {"id": 4}, "tenant": {"id": 7}, "role": {"id": 1}, "description": "Servers",
+			 "tags": [], "custom_fields": {"owner": "network team"}},
+			{"id": 10, "vid": 17, "name": "VLAN-17", "status": {"value": "deprecated", "label": "Deprecated"},
+			 "site": {"id": 2}, "group": null, "tenant": null, "role": null,
+			 "tags": [], "custom_fields": {"owner": "application team"}}
+		]}`},
+	})
+	defer server.Close()
+
+	for filter, expected := range map[string][]map[string]interface{}{
+		"": {
+			{"vlan_id": 9, "vid": 16, "name": "VLAN-16", "status": "active", "site_id": 2, "group_id": 4, "tenant_id": 7, "role_id": 1, "description": "Servers"},
+			{"vlan_id": 10, "vid": 17, "name": "VLAN-17", "status": "deprecated", "site_id": 2, "group_id": 0, "tenant_id": 0, "role_id": 0, "description": ""},
+		},
+		"application": {
+			{"vlan_id": 10, "vid": 17, "name": "VLAN-17"},
+		},
+		"^$": {},
+	} {
+		raw := map[string]interface{}{"site": "Inkopolis"}
+		if filter != "" {
+			raw["custom_field_filter"] = map[string]interface{}{"owner": filter}
+		}
+		d := schema.TestResourceDataRaw(t, dataSourceNetboxIpamVlans().Schema, raw)
+
+		if err := dataSourceNetboxIpamVlansRead(d, server.meta(t)); err != nil {
+			t.Fatalf("%q: err: %s", filter, err)
+		}
+
+		vlans := d.Get("vlans").([]interface{})
+		if len(vlans) != len(expected) {
+			t.Fatalf("%q: expected %d VLANs, got %v", filter, len(expected), vlans)
+		}
+		for i, values := range expected {
+			vlan := vlans[i].(map[string]interface{})
+			for name, value := range values {
+				if actual := vlan[name]; actual != value {
+					t.Errorf("%q: VLAN %d: expected %s to be %v, got %v", filter, i, name, value, actual)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package netbox
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client/tenancy"
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceNetboxOrgTenant looks up a tenant by name, slug or ID.
+func dataSourceNetboxOrgTenant() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetboxOrgTenantRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"comments": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tenant_group_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceNetboxOrgTenantRead finds the single tenant matching the name,
+// slug and/or ID.
+func dataSourceNetboxOrgTenantRead(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	name, nameOk := d.GetOk("name")
+	slug, slugOk := d.GetOk("slug")
+	id, idOk := d.GetOk("tenant_id")
+
+	if !nameOk && !slugOk && !idOk {
+		return fmt.Errorf("one of name, slug or tenant_id must be set")
+	}
+
+	var candidates []*models.Tenant
+
+	if idOk {
+		readParams := tenancy.NewTenancyTenantsReadParams().WithID(int64(id.(int)))
+
+		log.Debugf("Executing TenancyTenantsRead against Netbox: %v", readParams)
+
+		out, err := netboxClient.Tenancy.TenancyTenantsRead(readParams, nil)
+
+		if err != nil {
+			log.Debugf("Failed to execute TenancyTenantsRead: %v", err)
+
+			if isNotFound(err) {
+				return fmt.Errorf("no tenant has ID %d", id)
+			}
+			return err
+		}
+
+		candidates = append(candidates, out.Payload)
+	} else {
+		// The pinned client has no slug filter, tenants are searched for the
+		// slug and matched exactly below.
+		param := tenancy.NewTenancyTenantsListParams()
+		if nameOk {
+			name := name.(string)
+			param.SetName(&name)
+		}
+		if slugOk {
+			slug := slug.(string)
+			param.SetQ(&slug)
+		}
+
+		limit := int64(listPageSize)
+		param.SetLimit(&limit)
+
+		log.Debugf("Executing TenancyTenantsList against Netbox: %v", param)
+
+		out, err := netboxClient.Tenancy.TenancyTenantsList(param, nil)
+
+		if err != nil {
+			log.Debugf("Failed to execute TenancyTenantsList: %v", err)
+
+			return err
+		}
+
+		candidates = out.Payload.Results
+	}
+
+	var matches []*models.Tenant
+	for _, tenant := range candidates {
+		if nameOk && (tenant.Name == nil || *tenant.Name != name.(string)) {
+			continue
+		}
+		if slugOk && (tenant.Slug == nil || *tenant.Slug != slug.(string)) {
+			continue
+		}
+		matches = append(matches, tenant)
+	}
+
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("no tenant matches name %q, slug %q and ID %d", name, slug, id)
+	case len(matches) > 1:
+		return fmt.Errorf("more than one tenant matches name %q and slug %q", name, slug)
+	}
+
+	tenant := matches[0]
+
+	d.SetId(fmt.Sprintf("org/tenant/%d", tenant.ID))
+
+	if err := d.Set("tenant_id", tenant.ID); err != nil {
+		return err
+	}
+	if err := d.Set("name", tenant.Name); err != nil {
+		return err
+	}
+	if err := d.Set("slug", tenant.Slug); err != nil {
+		return err
+	}
+	if err := d.Set("description", tenant.Description); err != nil {
+		return err
+	}
+	if err := d.Set("comments", tenant.Comments); err != nil {
+		return err
+	}
+
+	var tenantGroupID int64
+	if tenant.Group != nil {
+		tenantGroupID = tenant.Group.ID
+	}
+	if err := d.Set("tenant_group_id", tenantGroupID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package netbox
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceNetboxOrgTenantGroup looks up a tenant group by name, slug or ID.
+//
+// Like the resource, it goes through doJSON to return the parent and
+// description of the group.
+func dataSourceNetboxOrgTenantGroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetboxOrgTenantGroupRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"tenant_group_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"parent_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceNetboxOrgTenantGroupRead finds the single tenant group matching
+// the name, slug and/or ID.
+func dataSourceNetboxOrgTenantGroupRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	name, nameOk := d.GetOk("name")
+	slug, slugOk := d.GetOk("slug")
+	id, idOk := d.GetOk("tenant_group_id")
+
+	if !nameOk && !slugOk && !idOk {
+		return fmt.Errorf("one of name, slug or tenant_group_id must be set")
+	}
+
+	query := url.Values{}
+	if nameOk {
+		query.Set("name", name.(string))
+	}
+	if slugOk {
+		query.Set("slug", slug.(string))
+	}
+	if idOk {
+		query.Set("id", strconv.Itoa(id.(int)))
+	}
+	query.Set("limit", "2")
+
+	log.Debugf("Looking up tenant group in Netbox: %v", query)
+
+	var out struct {
+		Results []tenantGroupPayload `json:"results"`
+	}
+	if err := c.doJSON(http.MethodGet, "/tenancy/tenant-groups/?"+query.Encode(), nil, &out); err != nil {
+		log.Debugf("Failed to look up tenant group: %v", err)
+
+		return err
+	}
+
+	switch {
+	case len(out.Results) == 0:
+		return fmt.Errorf("no tenant group matches name %q, slug %q and ID %d", name, slug, id)
+	case len(out.Results) > 1:
+		return fmt.Errorf("more than one tenant group matches name %q and slug %q", name, slug)
+	}
+
+	tenantGroup := out.Results[0]
+
+	d.SetId(fmt.Sprintf("org/tenant-group/%d", tenantGroup.ID))
+
+	return setOrgTenantGroup(d, &tenantGroup)
+}
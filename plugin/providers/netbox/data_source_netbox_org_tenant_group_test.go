@@ -0,0 +1,59 @@
+package netbox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceNetboxOrgTenantGroup_read(t *testing.T) {
+	group := `{"count": 1, "results": [{"id": 2, "name": "Splatoon Tenants", "slug": "splatoon", "parent": {"id": 1}, "description": "Tenants of the Splatoon games."}]}`
+
+	server := newTestNetboxServer(t, "2.10", map[string]testRoute{
+		"GET /api/tenancy/tenant-groups/?id=2&limit=2":                  {body: group},
+		"GET /api/tenancy/tenant-groups/?limit=2&name=Splatoon+Tenants": {body: group},
+		"GET /api/tenancy/tenant-groups/?limit=2&slug=splatoon":         {body: group},
+		"GET /api/tenancy/tenant-groups/?limit=2&slug=octoling":         {body: `{"count": 0, "results": []}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for _, raw := range []map[string]interface{}{
+		{"tenant_group_id": 2},
+		{"name": "Splatoon Tenants"},
+		{"slug": "splatoon"},
+	} {
+		d := schema.TestResourceDataRaw(t, dataSourceNetboxOrgTenantGroup().Schema, raw)
+
+		if err := dataSourceNetboxOrgTenantGroupRead(d, meta); err != nil {
+			t.Fatalf("%v: err: %s", raw, err)
+		}
+
+		expected := map[string]interface{}{
+			"tenant_group_id": 2,
+			"name":            "Splatoon Tenants",
+			"slug":            "splatoon",
+			"parent_id":       1,
+			"description":     "Tenants of the Splatoon games.",
+		}
+		for name, value := range expected {
+			if actual := d.Get(name); actual != value {
+				t.Errorf("%v: expected %s to be %v, got %v", raw, name, value, actual)
+			}
+		}
+		if d.Id() != "org/tenant-group/2" {
+			t.Errorf("%v: expected ID org/tenant-group/2, got %q", raw, d.Id())
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceNetboxOrgTenantGroup().Schema, map[string]interface{}{"slug": "octoling"})
+	if err := dataSourceNetboxOrgTenantGroupRead(d, meta); err == nil || err.Error() != `no tenant group matches name "", slug "octoling" and ID 0` {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceNetboxOrgTenantGroup().Schema, map[string]interface{}{})
+	if err := dataSourceNetboxOrgTenantGroupRead(d, meta); err == nil || err.Error() != "one of name, slug or tenant_group_id must be set" {
+		t.Fatalf("expected a missing search term error, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package netbox
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceNetboxOrgTenant_read(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/tenancy/tenants/7/":                          {body: testFixture(t, "read", "org_tenant.json")},
+		"GET /api/tenancy/tenants/?limit=1000&name=Squid+Kids": {body: `{"count": 1, "results": [{"id": 7, "name": "Squid Kids", "slug": "squid-kids", "group": {"id": 2}}]}`},
+		"GET /api/tenancy/tenants/?limit=1000&q=squid-kids": {body: `{"count": 2, "results": [
+			{"id": 8, "name": "Squid Kids Juniors", "slug": "squid-kids-juniors"},
+			{"id": 7, "name": "Squid Kids", "slug": "squid-kids", "group": {"id": 2}}
+		]}`},
+		"GET /api/tenancy/tenants/?limit=1000&q=octolings": {body: `{"count": 0, "results": []}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for _, raw := range []map[string]interface{}{
+		{"tenant_id": 7},
+		{"name": "Squid Kids"},
+		{"slug": "squid-kids"},
+	} {
+		d := schema.TestResourceDataRaw(t, dataSourceNetboxOrgTenant().Schema, raw)
+
+		if err := dataSourceNetboxOrgTenantRead(d, meta); err != nil {
+			t.Fatalf("%v: err: %s", raw, err)
+		}
+
+		expected := map[string]interface{}{
+			"tenant_id":       7,
+			"name":            "Squid Kids",
+			"slug":            "squid-kids",
+			"tenant_group_id": 2,
+		}
+		for name, value := range expected {
+			if actual := d.Get(name); actual != value {
+				t.Errorf("%v: expected %s to be %v, got %v", raw, name, value, actual)
+			}
+		}
+		if d.Id() != "org/tenant/7" {
+			t.Errorf("%v: expected ID org/tenant/7, got %q", raw, d.Id())
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceNetboxOrgTenant().Schema, map[string]interface{}{"slug": "octolings"})
+	if err := dataSourceNetboxOrgTenantRead(d, meta); err == nil || err.Error() != `no tenant matches name "", slug "octolings" and ID 0` {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceNetboxOrgTenant().Schema, map[string]interface{}{"tenant_id": 42})
+	if err := dataSourceNetboxOrgTenantRead(d, meta); err == nil || err.Error() != "no tenant has ID 42" {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceNetboxOrgTenant().Schema, map[string]interface{}{})
+	if err := dataSourceNetboxOrgTenantRead(d, meta); err == nil || err.Error() != "one of name, slug or tenant_id must be set" {
+		t.Fatalf("expected a missing search term error, got %v", err)
+	}
+}
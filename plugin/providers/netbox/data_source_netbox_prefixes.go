@@ -2,13 +2,14 @@ package netbox
 
 import (
 	"errors"
-	//"fmt"
+	"fmt"
 	"log"
 	"strconv"
-  "strings"
+	"strings"
 
 	// "errors"
 
+	"github.com/tpretz/go-netbox/netbox/client"
 	"github.com/tpretz/go-netbox/netbox/client/ipam"
 	"github.com/tpretz/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -22,106 +23,143 @@ func dataSourceNetboxPrefixes() *schema.Resource {
 }
 
 func dataSourceNetboxPrefixParse(d *schema.ResourceData, obj *models.Prefix) {
-  d.SetId(strconv.FormatInt(obj.ID, 10))
-  d.Set("created", obj.Created.String())
-  d.Set("description", obj.Description)
-  d.Set("family", obj.Family)
-  d.Set("is_pool", obj.IsPool)
-  d.Set("prefix", obj.Prefix)
-  d.Set("last_updated", obj.LastUpdated)
-
-  if obj.Vlan != nil {
-    d.Set("vlan_vid", *obj.Vlan.Vid)
-  }
-
-  log.Printf("Finished parsing results from IPAMPrefixesRead")
+	d.SetId(fmt.Sprintf("ipam/prefix/%d", obj.ID))
+	d.Set("created", obj.Created.String())
+	d.Set("description", obj.Description)
+	d.Set("family", obj.Family)
+	d.Set("is_pool", obj.IsPool)
+	d.Set("prefix", obj.Prefix)
+	d.Set("last_updated", obj.LastUpdated)
+
+	if obj.Vlan != nil {
+		d.Set("vlan_vid", *obj.Vlan.Vid)
+	}
+
+	log.Printf("Finished parsing results from IPAMPrefixesRead")
 }
 
 func dataSourceNetboxPrefixAttrPrep(in string) (out string) {
-  lowerstr := strings.ToLower(in)
-  out = strings.Replace(lowerstr, " ", "-", -1)
+	lowerstr := strings.ToLower(in)
+	out = strings.Replace(lowerstr, " ", "-", -1)
 
-  return
+	return
 }
 
 // Read will fetch the data of a resource.
 func dataSourceNetboxPrefixesRead(d *schema.ResourceData, meta interface{}) error {
-  c := meta.(*ProviderNetboxClient).client
+	c := meta.(*ProviderNetboxClient).client
 
-  // primary key lookup, direct
-  if id, idOk := d.GetOk("prefixes_id"); idOk {
-    parm := ipam.NewIPAMPrefixesReadParams()
+	// primary key lookup, direct
+	if id, idOk := d.GetOk("prefixes_id"); idOk {
+		parm := ipam.NewIPAMPrefixesReadParams()
 		parm.SetID(int64(id.(int)))
 
 		out, err := c.IPAM.IPAMPrefixesRead(parm, nil)
 
 		if err != nil {
-      log.Printf("error from IPAMPrefixesRead: %v\n", err)
+			log.Printf("error from IPAMPrefixesRead: %v\n", err)
 			return err
-    }
+		}
 
-    dataSourceNetboxPrefixParse(d, out.Payload)
-  } else { // anything else, requires a search
-    param := ipam.NewIPAMPrefixesListParams()
+		dataSourceNetboxPrefixParse(d, out.Payload)
+	} else { // anything else, requires a search
+		filter := prefixFilter{
+			Query:  d.Get("query").(string),
+			Within: d.Get("within").(string),
+			Family: d.Get("family").(string),
+			Tenant: d.Get("tenant").(string),
+			Site:   d.Get("site").(string),
+			Role:   d.Get("role").(string),
+		}
+		if vid, vidOk := d.GetOk("vlan_vid"); vidOk {
+			filter.VlanVid = vid.(int)
+		}
 
-    // Add any lookup params
+		prefix, err := findPrefix(c, filter)
 
-    if vid, vidOk := d.GetOk("vlan_vid"); vidOk {
-      vlan_vid := float64(vid.(int))
-      param.SetVlanVid(&vlan_vid)
-    }
+		if err != nil {
+			return err
+		}
 
-    if query, queryOk := d.GetOk("query"); queryOk {
-      query_str := query.(string)
-      param.SetQ(&query_str)
-    }
+		dataSourceNetboxPrefixParse(d, prefix)
+	}
 
-    if within, withinOk := d.GetOk("within"); withinOk {
-      within_str := within.(string)
-      param.SetWithin(&within_str)
-    }
+	return nil
+}
 
-    if family, familyOk := d.GetOk("family"); familyOk {
-      family_str := family.(string)
-      param.SetFamily(&family_str)
-    }
+// prefixFilter holds the search terms of a prefix lookup, empty ones are
+// ignored. Tenant, site and role are matched by slug, derived from the name.
+type prefixFilter struct {
+	VlanVid int
+	Query   string
+	Within  string
+	Family  string
+	Tenant  string
+	Site    string
+	Role    string
+}
 
-    if tenant, tenantOk := d.GetOk("tenant"); tenantOk {
-      tenant_str := dataSourceNetboxPrefixAttrPrep(tenant.(string))
-      param.SetTenant(&tenant_str)
-    }
+// findPrefix returns the single prefix matching filter.
+func findPrefix(c *client.NetBox, filter prefixFilter) (*models.Prefix, error) {
+	param := ipam.NewIPAMPrefixesListParams()
 
-    if site, siteOk := d.GetOk("site"); siteOk {
-      site_str := dataSourceNetboxPrefixAttrPrep(site.(string))
-      param.SetSite(&site_str)
-    }
+	// Add any lookup params
 
-    if role, roleOk := d.GetOk("role"); roleOk {
-      role_str := dataSourceNetboxPrefixAttrPrep(role.(string))
-      param.SetRole(&role_str)
-    }
+	if filter.VlanVid != 0 {
+		vlan_vid := float64(filter.VlanVid)
+		param.SetVlanVid(&vlan_vid)
+	}
 
-    // limit to 2
-    limit := int64(2)
-    param.SetLimit(&limit)
+	if filter.Query != "" {
+		param.SetQ(&filter.Query)
+	}
 
-		out, err := c.IPAM.IPAMPrefixesList(param, nil)
+	if filter.Within != "" {
+		param.SetWithin(&filter.Within)
+	}
 
+	if filter.Family != "" {
+		family, err := familyChoices.parse(filter.Family)
 		if err != nil {
-      log.Printf("error from IPAMPrefixesList: %v\n", err)
-			return err
-    }
+			return nil, err
+		}
+		family_str := strconv.FormatInt(family, 10)
+		param.SetFamily(&family_str)
+	}
 
-    if *out.Payload.Count == 0 {
-			return errors.New("Prefix not found")
-    } else if *out.Payload.Count > 1 {
-      return errors.New("More than one prefix matches search terms, please narrow")
-    }
+	if filter.Tenant != "" {
+		tenant_str := dataSourceNetboxPrefixAttrPrep(filter.Tenant)
+		param.SetTenant(&tenant_str)
+	}
 
-    dataSourceNetboxPrefixParse(d, out.Payload.Results[0])
-  }
+	if filter.Site != "" {
+		site_str := dataSourceNetboxPrefixAttrPrep(filter.Site)
+		param.SetSite(&site_str)
+	}
 
-	return nil
+	if filter.Role != "" {
+		role_str := dataSourceNetboxPrefixAttrPrep(filter.Role)
+		param.SetRole(&role_str)
+	}
+
+	// limit to 2
+	limit := int64(2)
+	param.SetLimit(&limit)
+
+	out, err := c.IPAM.IPAMPrefixesList(param, nil)
+
+	if err != nil {
+		log.Printf("error from IPAMPrefixesList: %v\n", err)
+		return nil, err
+	}
+
+	if *out.Payload.Count == 0 {
+		return nil, errors.New("Prefix not found")
+	} else if *out.Payload.Count > 1 {
+		return nil, errors.New("More than one prefix matches search terms, please narrow")
+	}
+
+	return out.Payload.Results[0], nil
 }
 
 func barePrefixesSchema() map[string]*schema.Schema {
@@ -154,24 +192,24 @@ func barePrefixesSchema() map[string]*schema.Schema {
 			Type: schema.TypeInt,
 		},
 		"query": &schema.Schema{
-			Type: schema.TypeString,
-      Optional: true,
+			Type:     schema.TypeString,
+			Optional: true,
 		},
 		"tenant": &schema.Schema{
-			Type: schema.TypeString,
-      Optional: true,
+			Type:     schema.TypeString,
+			Optional: true,
 		},
 		"site": &schema.Schema{
-			Type: schema.TypeString,
-      Optional: true,
+			Type:     schema.TypeString,
+			Optional: true,
 		},
 		"role": &schema.Schema{
-			Type: schema.TypeString,
-      Optional: true,
+			Type:     schema.TypeString,
+			Optional: true,
 		},
 		"within": &schema.Schema{
-			Type: schema.TypeString,
-      Optional: true,
+			Type:     schema.TypeString,
+			Optional: true,
 		},
 	}
 }
@@ -224,6 +262,7 @@ func dataSourcePrefixesSchema() map[string]*schema.Schema {
 			v.Optional = true
 		case "family":
 			v.Optional = true
+			v.ValidateFunc = familyChoices.validate
 		case "created":
 			v.Optional = true
 			//v.ConflictsWith = []string{"ip_address", "subnet_id", "description", "hostname", "custom_field_filter"}
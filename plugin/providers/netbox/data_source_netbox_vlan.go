@@ -2,8 +2,8 @@ package netbox
 
 import (
 	"errors"
+	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/tpretz/go-netbox/netbox/client/ipam"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -39,7 +39,7 @@ func dataSourceNetboxVlansRead(d *schema.ResourceData, meta interface{}) error {
 				return errors.New("More than one Vid found with name " + d.Get("name").(string))
 			}
 			result := out.Payload.Results[0]
-			d.SetId(strconv.Itoa(int(result.ID)))
+			d.SetId(fmt.Sprintf("ipam/vlan/%d", result.ID))
 			d.Set("created", result.Created)
 			d.Set("description", result.Description)
 			d.Set("display_name", result.DisplayName)
@@ -74,7 +74,7 @@ func dataSourceNetboxVlansRead(d *schema.ResourceData, meta interface{}) error {
 				return errors.New("More than one vlan found with name " + d.Get("name").(string))
 			}
 			result := out.Payload.Results[0]
-			d.SetId(strconv.Itoa(int(result.ID)))
+			d.SetId(fmt.Sprintf("ipam/vlan/%d", result.ID))
 			d.Set("created", result.Created)
 			d.Set("description", result.Description)
 			d.Set("display_name", result.DisplayName)
@@ -159,40 +159,6 @@ func bareVlanSchema() map[string]*schema.Schema {
 	}
 }
 
-func resourceVlansSchema() map[string]*schema.Schema {
-	s := bareVlanSchema()
-
-	for k, v := range s {
-		switch k {
-		case "vid":
-			v.Computed = true
-			v.Optional = true
-		case "name":
-			v.Optional = true
-			v.Computed = true
-		case "custom_fields":
-			v.Optional = true
-		case "status":
-			v.Optional = true
-		case "role":
-			v.Optional = true
-			//v.ConflictsWith = []string{"ip_address", "subnet_id", "description", "hostname", "custom_field_filter"}
-		default:
-			v.Computed = true
-		}
-	}
-	// Add the remove_dns_on_delete item to the schema. This is a meta-parameter
-	// that is not part of the API resource and exists to instruct NETBOX to
-	// gracefully remove the address from its DNS integrations as well when it is
-	// removed. The default on this option is true.
-	s["remove_dns_on_delete"] = &schema.Schema{
-		Type:     schema.TypeBool,
-		Optional: true,
-		Default:  true,
-	}
-	return s
-}
-
 // dataSourceAddressSchema returns the schema for the NETBOX_VLANS data
 // source. It sets the searchable fields and sets up the attribute conflicts
 // between IP address and address ID. It also ensures that all fields are
@@ -0,0 +1,88 @@
+package netbox
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tpretz/go-netbox/netbox/client"
+)
+
+// apiEndpoint is the canonical location of the Netbox API, shared by the
+// generated client and any raw HTTP request the provider issues.
+type apiEndpoint struct {
+	// Scheme is either http or https.
+	Scheme string
+
+	// Host includes the port when one was given.
+	Host string
+
+	// BasePath is the path of the API root without a trailing slash, e.g.
+	// /api or /netbox/api for an instance served under a sub-path.
+	BasePath string
+}
+
+// parseEndpoint turns the endpoint provider argument into an apiEndpoint.
+// Bare host names default to http, and the /api suffix is appended to the
+// path unless it is already present, so all of the following are accepted:
+//
+//	netbox.example.com
+//	netbox.example.com:8080/
+//	https://netbox.example.com/api/
+//	https://tools.example.com/netbox
+func parseEndpoint(raw string) (*apiEndpoint, error) {
+	raw = strings.TrimSpace(raw)
+
+	if raw == "" {
+		return nil, fmt.Errorf("endpoint must not be empty")
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	parsedURI, err := url.Parse(raw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := strings.ToLower(parsedURI.Scheme)
+
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("endpoint %q: unsupported scheme %q, expected http or https", raw, parsedURI.Scheme)
+	}
+
+	if parsedURI.Host == "" {
+		return nil, fmt.Errorf("endpoint %q: missing host", raw)
+	}
+
+	if parsedURI.RawQuery != "" || parsedURI.Fragment != "" {
+		return nil, fmt.Errorf("endpoint %q: must not contain a query or fragment", raw)
+	}
+
+	basePath := strings.TrimRight(parsedURI.Path, "/")
+
+	if !strings.HasSuffix(basePath, client.DefaultBasePath) {
+		basePath += client.DefaultBasePath
+	}
+
+	return &apiEndpoint{
+		Scheme:   scheme,
+		Host:     parsedURI.Host,
+		BasePath: basePath,
+	}, nil
+}
+
+// URL returns the absolute URL of an API path such as /ipam/prefixes/.
+func (e *apiEndpoint) URL(path string) string {
+	return (&url.URL{
+		Scheme: e.Scheme,
+		Host:   e.Host,
+		Path:   e.BasePath + "/" + strings.TrimLeft(path, "/"),
+	}).String()
+}
+
+func (e *apiEndpoint) String() string {
+	return e.URL("/")
+}
@@ -0,0 +1,87 @@
+package netbox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected apiEndpoint
+	}{
+		{"netbox.example.com", apiEndpoint{"http", "netbox.example.com", "/api"}},
+		{"netbox.example.com:8080", apiEndpoint{"http", "netbox.example.com:8080", "/api"}},
+		{"localhost:8000/", apiEndpoint{"http", "localhost:8000", "/api"}},
+		{"http://netbox.example.com", apiEndpoint{"http", "netbox.example.com", "/api"}},
+		{"https://netbox.example.com/", apiEndpoint{"https", "netbox.example.com", "/api"}},
+		{"HTTPS://netbox.example.com:8443//", apiEndpoint{"https", "netbox.example.com:8443", "/api"}},
+		{"https://netbox.example.com/api", apiEndpoint{"https", "netbox.example.com", "/api"}},
+		{"https://netbox.example.com/api/", apiEndpoint{"https", "netbox.example.com", "/api"}},
+		{"https://tools.example.com/netbox", apiEndpoint{"https", "tools.example.com", "/netbox/api"}},
+		{"https://tools.example.com/netbox/", apiEndpoint{"https", "tools.example.com", "/netbox/api"}},
+		{"https://tools.example.com/netbox/api", apiEndpoint{"https", "tools.example.com", "/netbox/api"}},
+		{"https://tools.example.com:8443/netbox/api/", apiEndpoint{"https", "tools.example.com:8443", "/netbox/api"}},
+		{"  https://netbox.example.com  ", apiEndpoint{"https", "netbox.example.com", "/api"}},
+	}
+
+	for _, tc := range cases {
+		actual, err := parseEndpoint(tc.raw)
+		if err != nil {
+			t.Fatalf("%q: err: %s", tc.raw, err)
+		}
+		if *actual != tc.expected {
+			t.Fatalf("%q: expected %#v, got %#v", tc.raw, tc.expected, *actual)
+		}
+	}
+}
+
+func TestParseEndpoint_invalid(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"ftp://netbox.example.com",
+		"https://",
+		"https://netbox.example.com/?token=abc",
+	} {
+		if _, err := parseEndpoint(raw); err == nil {
+			t.Fatalf("%q: expected error", raw)
+		}
+	}
+}
+
+func TestAPIEndpointURL(t *testing.T) {
+	e := apiEndpoint{"https", "tools.example.com:8443", "/netbox/api"}
+
+	expected := "https://tools.example.com:8443/netbox/api/ipam/prefixes/42/available-ips/"
+	if actual := e.URL("/ipam/prefixes/42/available-ips/"); actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+	if actual := e.URL("ipam/prefixes/42/available-ips/"); actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestConfigClient_subPath(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 0, "results": []}`))
+	}))
+	defer server.Close()
+
+	meta, err := (&Config{Endpoint: server.URL + "/netbox/"}).Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := meta.(*ProviderNetboxClient).client.IPAM.IPAMRirsList(ipam.NewIPAMRirsListParams(), nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requested != "/netbox/api/ipam/rirs/" {
+		t.Fatalf("unexpected request path %q", requested)
+	}
+}
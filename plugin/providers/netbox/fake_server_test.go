@@ -0,0 +1,116 @@
+package netbox
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// testRoute is a canned response of the fake Netbox server. When handler is
+// set, it computes the response instead.
+type testRoute struct {
+	status  int
+	body    string
+	handler func(r testRequest) testRoute
+}
+
+// testRequest is a request received by the fake Netbox server.
+type testRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+// testNetboxServer is a stand-in for the Netbox API. Routes are keyed by
+// "METHOD /path", and every response carries the configured API-Version.
+type testNetboxServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string]testRoute
+	requests []testRequest
+}
+
+func newTestNetboxServer(t *testing.T, version string, routes map[string]testRoute) *testNetboxServer {
+	s := &testNetboxServer{routes: routes}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		request := testRequest{method: r.Method, path: r.URL.RequestURI(), body: string(body)}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		route, ok := s.routes[r.Method+" "+r.URL.RequestURI()]
+		if !ok {
+			route, ok = s.routes[r.Method+" "+r.URL.Path]
+		}
+		s.mu.Unlock()
+
+		if ok && route.handler != nil {
+			route = route.handler(request)
+		}
+
+		w.Header().Set("API-Version", version)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case ok:
+			status := route.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(route.body))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/":
+			w.Write([]byte(`{"ipam": "/api/ipam/", "tenancy": "/api/tenancy/"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail": "Not found."}`))
+		}
+	}))
+
+	return s
+}
+
+// setRoute adds or replaces a canned response.
+func (s *testNetboxServer) setRoute(key string, route testRoute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[key] = route
+}
+
+// received returns the requests received so far, excluding version detection.
+func (s *testNetboxServer) received() []testRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []testRequest
+	for _, r := range s.requests {
+		if r.path != "/api/" {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// meta returns a configured provider client pointing at the fake server.
+func (s *testNetboxServer) meta(t *testing.T) *ProviderNetboxClient {
+	meta, err := (&Config{AppID: "0123456789abcdef", Endpoint: s.URL}).Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return meta.(*ProviderNetboxClient)
+}
+
+// testFixture reads a recorded API payload from testdata.
+func testFixture(t *testing.T, path ...string) string {
+	content, err := ioutil.ReadFile(filepath.Join(append([]string{"testdata"}, path...)...))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return string(content)
+}
@@ -0,0 +1,302 @@
+package netbox
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client"
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/tpretz/go-netbox/netbox/client/tenancy"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// importNumericID returns the Netbox ID of an import ID given either as a
+// number ("42") or as a resource ID ("ipam/prefix/42"). ok is false when the
+// import ID is neither, and should be looked up as a natural key.
+func importNumericID(importID, kind string) (id int64, ok bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(importID, kind+"/"), 10, 64)
+	return id, err == nil
+}
+
+// importedState sets the resource ID, "<kind>/<id>", and the attribute holding
+// the Netbox ID of an imported resource.
+func importedState(d *schema.ResourceData, kind, idKey string, id int64) ([]*schema.ResourceData, error) {
+	d.SetId(fmt.Sprintf("%s/%d", kind, id))
+	if err := d.Set(idKey, id); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// splitVrfCIDR splits the natural key of a prefix or IP address,
+// "<vrf name>/<address>/<mask>", or "<address>/<mask>" in the global table.
+// The VRF name is matched from the right so it may contain slashes itself.
+func splitVrfCIDR(key string) (vrfName string, ip net.IP, ipNet *net.IPNet, ok bool) {
+	mask := strings.LastIndex(key, "/")
+	if mask < 0 {
+		return "", nil, nil, false
+	}
+
+	start := strings.LastIndex(key[:mask], "/") + 1
+
+	ip, ipNet, err := net.ParseCIDR(key[start:])
+	if err != nil {
+		return "", nil, nil, false
+	}
+
+	return strings.TrimSuffix(key[:start], "/"), ip, ipNet, true
+}
+
+// importVrfFilter returns the vrf_id filter of a natural key lookup: the ID
+// of the named VRF, or "null" for the global table.
+func importVrfFilter(netboxClient *client.NetBox, vrfName string) (string, error) {
+	if vrfName == "" {
+		return "null", nil
+	}
+
+	param := ipam.NewIPAMVrfsListParams()
+	param.SetName(&vrfName)
+
+	limit := int64(2)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing IPAMVrfsList against Netbox: %v", param)
+
+	out, err := netboxClient.IPAM.IPAMVrfsList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMVrfsList: %v", err)
+
+		return "", err
+	}
+
+	switch {
+	case len(out.Payload.Results) == 0:
+		return "", fmt.Errorf("no VRF is named %q", vrfName)
+	case len(out.Payload.Results) > 1:
+		return "", fmt.Errorf("more than one VRF is named %q", vrfName)
+	}
+
+	return strconv.FormatInt(out.Payload.Results[0].ID, 10), nil
+}
+
+// prefixIDByKey returns the ID of the prefix matching a natural key such as
+// "Toni Kensa/10.0.0.0/24".
+func prefixIDByKey(netboxClient *client.NetBox, key string) (int64, error) {
+	vrfName, _, ipNet, ok := splitVrfCIDR(key)
+	if !ok {
+		return 0, fmt.Errorf("invalid prefix ID %q, expected a number or <vrf name>/<prefix>", key)
+	}
+
+	vrfID, err := importVrfFilter(netboxClient, vrfName)
+	if err != nil {
+		return 0, err
+	}
+
+	// The pinned client has no exact prefix filter, the prefix is the only
+	// one of its length within itself.
+	prefix := ipNet.String()
+	length, _ := ipNet.Mask.Size()
+	maskLength := float64(length)
+
+	param := ipam.NewIPAMPrefixesListParams()
+	param.SetWithinInclude(&prefix)
+	param.SetMaskLength(&maskLength)
+	param.SetVrfID(&vrfID)
+
+	limit := int64(2)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing IPAMPrefixesList against Netbox: %v", param)
+
+	out, err := netboxClient.IPAM.IPAMPrefixesList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMPrefixesList: %v", err)
+
+		return 0, err
+	}
+
+	switch {
+	case len(out.Payload.Results) == 0:
+		return 0, fmt.Errorf("no prefix matches %q", key)
+	case len(out.Payload.Results) > 1:
+		return 0, fmt.Errorf("more than one prefix matches %q", key)
+	}
+
+	return out.Payload.Results[0].ID, nil
+}
+
+// ipAddressIDByKey returns the ID of the IP address matching a natural key
+// such as "Toni Kensa/10.0.0.1/24".
+func ipAddressIDByKey(netboxClient *client.NetBox, key string) (int64, error) {
+	vrfName, ip, ipNet, ok := splitVrfCIDR(key)
+	if !ok {
+		return 0, fmt.Errorf("invalid IP address ID %q, expected a number or <vrf name>/<address>", key)
+	}
+
+	vrfID, err := importVrfFilter(netboxClient, vrfName)
+	if err != nil {
+		return 0, err
+	}
+
+	length, _ := ipNet.Mask.Size()
+	address := fmt.Sprintf("%s/%d", ip, length)
+
+	param := ipam.NewIPAMIPAddressesListParams()
+	param.SetAddress(&address)
+	param.SetVrfID(&vrfID)
+
+	limit := int64(listPageSize)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing IPAMIPAddressesList against Netbox: %v", param)
+
+	out, err := netboxClient.IPAM.IPAMIPAddressesList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMIPAddressesList: %v", err)
+
+		return 0, err
+	}
+
+	// Netbox matches the address whatever its mask, keep the exact one.
+	var ids []int64
+	for _, result := range out.Payload.Results {
+		if result.Address != nil && *result.Address == address {
+			ids = append(ids, result.ID)
+		}
+	}
+
+	switch {
+	case len(ids) == 0:
+		return 0, fmt.Errorf("no IP address matches %q", key)
+	case len(ids) > 1:
+		return 0, fmt.Errorf("more than one IP address matches %q", key)
+	}
+
+	return ids[0], nil
+}
+
+// aggregateIDByPrefix returns the ID of the aggregate of the given prefix.
+func aggregateIDByPrefix(netboxClient *client.NetBox, key string) (int64, error) {
+	_, ipNet, err := net.ParseCIDR(key)
+	if err != nil {
+		return 0, fmt.Errorf("invalid aggregate ID %q, expected a number or a prefix", key)
+	}
+
+	// The pinned client has no prefix filter, aggregates are searched for
+	// the prefix and matched exactly below.
+	prefix := ipNet.String()
+
+	param := ipam.NewIPAMAggregatesListParams()
+	param.SetQ(&prefix)
+
+	limit := int64(listPageSize)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing IPAMAggregatesList against Netbox: %v", param)
+
+	out, err := netboxClient.IPAM.IPAMAggregatesList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMAggregatesList: %v", err)
+
+		return 0, err
+	}
+
+	for _, result := range out.Payload.Results {
+		if result.Prefix != nil && *result.Prefix == prefix {
+			return result.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no aggregate matches %q", key)
+}
+
+// vrfIDByRD returns the ID of the VRF with the given route distinguisher.
+func vrfIDByRD(netboxClient *client.NetBox, rd string) (int64, error) {
+	param := ipam.NewIPAMVrfsListParams()
+	param.SetRd(&rd)
+
+	limit := int64(2)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing IPAMVrfsList against Netbox: %v", param)
+
+	out, err := netboxClient.IPAM.IPAMVrfsList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMVrfsList: %v", err)
+
+		return 0, err
+	}
+
+	switch {
+	case len(out.Payload.Results) == 0:
+		return 0, fmt.Errorf("no VRF has route distinguisher %q", rd)
+	case len(out.Payload.Results) > 1:
+		return 0, fmt.Errorf("more than one VRF has route distinguisher %q", rd)
+	}
+
+	return out.Payload.Results[0].ID, nil
+}
+
+// tenantIDBySlug returns the ID of the tenant with the given slug.
+func tenantIDBySlug(netboxClient *client.NetBox, slug string) (int64, error) {
+	// The pinned client has no slug filter, tenants are searched for the
+	// slug and matched exactly below.
+	param := tenancy.NewTenancyTenantsListParams()
+	param.SetQ(&slug)
+
+	limit := int64(listPageSize)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing TenancyTenantsList against Netbox: %v", param)
+
+	out, err := netboxClient.Tenancy.TenancyTenantsList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute TenancyTenantsList: %v", err)
+
+		return 0, err
+	}
+
+	for _, result := range out.Payload.Results {
+		if result.Slug != nil && *result.Slug == slug {
+			return result.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no tenant matches slug %q", slug)
+}
+
+// tenantGroupIDBySlug returns the ID of the tenant group with the given slug.
+func tenantGroupIDBySlug(netboxClient *client.NetBox, slug string) (int64, error) {
+	param := tenancy.NewTenancyTenantGroupsListParams()
+	param.SetSlug(&slug)
+
+	limit := int64(2)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing TenancyTenantGroupsList against Netbox: %v", param)
+
+	out, err := netboxClient.Tenancy.TenancyTenantGroupsList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute TenancyTenantGroupsList: %v", err)
+
+		return 0, err
+	}
+
+	if len(out.Payload.Results) != 1 {
+		return 0, fmt.Errorf("no tenant group matches slug %q", slug)
+	}
+
+	return out.Payload.Results[0].ID, nil
+}
@@ -0,0 +1,238 @@
+package netbox
+
+import (
+	"testing"
+)
+
+// testImports lists the import IDs accepted by each resource, and the
+// lookups natural keys translate to.
+var testImports = []struct {
+	name     string
+	importID string
+	routes   map[string]testRoute
+	id       string
+	idKey    string
+}{
+	{
+		name:     "netbox_ipam_prefix",
+		importID: "42",
+		id:       "ipam/prefix/42",
+		idKey:    "prefix_id",
+	},
+	{
+		name:     "netbox_ipam_prefix",
+		importID: "ipam/prefix/42",
+		id:       "ipam/prefix/42",
+		idKey:    "prefix_id",
+	},
+	{
+		name:     "netbox_ipam_prefix",
+		importID: "Toni Kensa/192.168.100.0/24",
+		routes: map[string]testRoute{
+			"GET /api/ipam/vrfs/?limit=2&name=Toni+Kensa":                                               {body: `{"count": 1, "results": [{"id": 3}]}`},
+			"GET /api/ipam/prefixes/?limit=2&mask_length=24&vrf_id=3&within_include=192.168.100.0%2F24": {body: `{"count": 1, "results": [{"id": 42}]}`},
+		},
+		id:    "ipam/prefix/42",
+		idKey: "prefix_id",
+	},
+	{
+		name:     "netbox_ipam_prefix",
+		importID: "192.168.100.0/24",
+		routes: map[string]testRoute{
+			"GET /api/ipam/prefixes/?limit=2&mask_length=24&vrf_id=null&within_include=192.168.100.0%2F24": {body: `{"count": 1, "results": [{"id": 42}]}`},
+		},
+		id:    "ipam/prefix/42",
+		idKey: "prefix_id",
+	},
+	{
+		name:     "netbox_ipam_ip_address",
+		importID: "ipam/ip-address/10",
+		id:       "ipam/ip-address/10",
+		idKey:    "ip_address_id",
+	},
+	{
+		name:     "netbox_ipam_ip_address",
+		importID: "Toni Kensa/192.168.100.1/24",
+		routes: map[string]testRoute{
+			"GET /api/ipam/vrfs/?limit=2&name=Toni+Kensa": {body: `{"count": 1, "results": [{"id": 3}]}`},
+			"GET /api/ipam/ip-addresses/?address=192.168.100.1%2F24&limit=1000&vrf_id=3": {body: `{"count": 2, "results": [
+				{"id": 11, "address": "192.168.100.1/32"},
+				{"id": 10, "address": "192.168.100.1/24"}
+			]}`},
+		},
+		id:    "ipam/ip-address/10",
+		idKey: "ip_address_id",
+	},
+	{
+		name:     "netbox_ipam_vrf",
+		importID: "3",
+		id:       "ipam/vrf/3",
+		idKey:    "vrf_id",
+	},
+	{
+		name:     "netbox_ipam_vrf",
+		importID: "toni-kensa-west",
+		routes: map[string]testRoute{
+			"GET /api/ipam/vrfs/?limit=2&rd=toni-kensa-west": {body: `{"count": 1, "results": [{"id": 3}]}`},
+		},
+		id:    "ipam/vrf/3",
+		idKey: "vrf_id",
+	},
+	{
+		name:     "netbox_ipam_aggregate",
+		importID: "2",
+		id:       "ipam/aggregate/2",
+		idKey:    "aggregate_id",
+	},
+	{
+		name:     "netbox_ipam_aggregate",
+		importID: "192.168.0.0/16",
+		routes: map[string]testRoute{
+			"GET /api/ipam/aggregates/?limit=1000&q=192.168.0.0%2F16": {body: `{"count": 2, "results": [
+				{"id": 5, "prefix": "192.168.0.0/24"},
+				{"id": 2, "prefix": "192.168.0.0/16"}
+			]}`},
+		},
+		id:    "ipam/aggregate/2",
+		idKey: "aggregate_id",
+	},
+	{
+		name:     "netbox_ipam_rir",
+		importID: "ipam/rir/1",
+		id:       "ipam/rir/1",
+		idKey:    "rir_id",
+	},
+	{
+		name:     "netbox_ipam_rir",
+		importID: "squidland",
+		routes: map[string]testRoute{
+			"GET /api/ipam/rirs/?limit=2&slug=squidland": {body: `{"count": 1, "results": [{"id": 1}]}`},
+		},
+		id:    "ipam/rir/1",
+		idKey: "rir_id",
+	},
+	{
+		name:     "netbox_org_tenant",
+		importID: "org/tenant/7",
+		id:       "org/tenant/7",
+		idKey:    "tenant_id",
+	},
+	{
+		name:     "netbox_org_tenant",
+		importID: "squid-kids",
+		routes: map[string]testRoute{
+			"GET /api/tenancy/tenants/?limit=1000&q=squid-kids": {body: `{"count": 2, "results": [
+				{"id": 8, "slug": "squid-kids-juniors"},
+				{"id": 7, "slug": "squid-kids"}
+			]}`},
+		},
+		id:    "org/tenant/7",
+		idKey: "tenant_id",
+	},
+	{
+		name:     "netbox_org_tenant_group",
+		importID: "2",
+		id:       "org/tenant-group/2",
+		idKey:    "tenant_group_id",
+	},
+	{
+		name:     "netbox_org_tenant_group",
+		importID: "splatoon",
+		routes: map[string]testRoute{
+			"GET /api/tenancy/tenant-groups/?limit=2&slug=splatoon": {body: `{"count": 1, "results": [{"id": 2}]}`},
+		},
+		id:    "org/tenant-group/2",
+		idKey: "tenant_group_id",
+	},
+}
+
+// testImportReadRoutes serves the objects imported by testImports.
+var testImportReadRoutes = map[string]string{
+	"GET /api/ipam/prefixes/42/":        "ipam_prefix.json",
+	"GET /api/ipam/ip-addresses/10/":    "ipam_ip_address.json",
+	"GET /api/ipam/vrfs/3/":             "ipam_vrf.json",
+	"GET /api/ipam/aggregates/2/":       "ipam_aggregate.json",
+	"GET /api/ipam/rirs/1/":             "ipam_rir.json",
+	"GET /api/tenancy/tenants/7/":       "org_tenant.json",
+	"GET /api/tenancy/tenant-groups/2/": "org_tenant_group.json",
+}
+
+func TestResourceImport(t *testing.T) {
+	for _, tc := range testImports {
+		t.Run(tc.name+" "+tc.importID, func(t *testing.T) {
+			routes := map[string]testRoute{}
+			for key, fixture := range testImportReadRoutes {
+				routes[key] = testRoute{body: testFixture(t, "read", fixture)}
+			}
+			routes["GET /api/dcim/devices/6/"] = testRoute{body: `{"id": 6, "primary_ip4": {"id": 10}, "primary_ip6": null}`}
+			for key, route := range tc.routes {
+				routes[key] = route
+			}
+
+			server := newTestNetboxServer(t, "2.5", routes)
+			defer server.Close()
+
+			meta := server.meta(t)
+
+			r := providerResources()[tc.name]
+			d := r.TestResourceData()
+			d.SetId(tc.importID)
+
+			imported, err := r.Importer.State(d, meta)
+			if err != nil {
+				t.Fatalf("err: %s (requests: %v)", err, server.received())
+			}
+			if len(imported) != 1 {
+				t.Fatalf("expected one imported resource, got %d", len(imported))
+			}
+
+			d = imported[0]
+			if d.Id() != tc.id {
+				t.Fatalf("expected ID %q, got %q", tc.id, d.Id())
+			}
+
+			// Read must find the imported object rather than ID 0.
+			if err := r.Read(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if d.Id() != tc.id {
+				t.Fatalf("expected ID %q after read, got %q (requests: %v)", tc.id, d.Id(), server.received())
+			}
+			if tc.idKey != "" && d.Get(tc.idKey) == 0 {
+				t.Fatalf("expected %s to be set", tc.idKey)
+			}
+		})
+	}
+}
+
+func TestResourceImport_errors(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/vrfs/?limit=2&name=Octo+Canyon":                                            {body: `{"count": 0, "results": []}`},
+		"GET /api/ipam/prefixes/?limit=2&mask_length=24&vrf_id=null&within_include=10.0.0.0%2F24": {body: `{"count": 0, "results": []}`},
+		"GET /api/ipam/vrfs/?limit=2&rd=octo-canyon":                                              {body: `{"count": 0, "results": []}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for _, tc := range []struct {
+		name     string
+		importID string
+		err      string
+	}{
+		{"netbox_ipam_prefix", "Octo Canyon/10.0.0.0/24", `no VRF is named "Octo Canyon"`},
+		{"netbox_ipam_prefix", "10.0.0.0/24", `no prefix matches "10.0.0.0/24"`},
+		{"netbox_ipam_prefix", "toni-kensa", `invalid prefix ID "toni-kensa", expected a number or <vrf name>/<prefix>`},
+		{"netbox_ipam_ip_address", "10.0.0.1", `invalid IP address ID "10.0.0.1", expected a number or <vrf name>/<address>`},
+		{"netbox_ipam_vrf", "octo-canyon", `no VRF has route distinguisher "octo-canyon"`},
+		{"netbox_ipam_aggregate", "squidland", `invalid aggregate ID "squidland", expected a number or a prefix`},
+	} {
+		r := providerResources()[tc.name]
+		d := r.TestResourceData()
+		d.SetId(tc.importID)
+
+		if _, err := r.Importer.State(d, meta); err == nil || err.Error() != tc.err {
+			t.Errorf("%s %q: expected error %q, got %v", tc.name, tc.importID, tc.err, err)
+		}
+	}
+}
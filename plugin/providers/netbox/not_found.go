@@ -0,0 +1,46 @@
+package netbox
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	"github.com/hashicorp/terraform/helper/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+// isNotFound reports whether err is the 404 response the generated client
+// returns for an object that does not exist in Netbox.
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*runtime.APIError)
+	return ok && apiErr.Code == http.StatusNotFound
+}
+
+// isConflict reports whether err is a 409 response, returned by allocation
+// endpoints when nothing is left to allocate.
+func isConflict(err error) bool {
+	apiErr, ok := err.(*runtime.APIError)
+	return ok && apiErr.Code == http.StatusConflict
+}
+
+// handleReadNotFound is meant for the error of a Read function's API call. An
+// object deleted outside of Terraform is removed from the state so it gets
+// planned for creation again, any other error is returned as is.
+func handleReadNotFound(d *schema.ResourceData, err error) error {
+	if !isNotFound(err) {
+		return err
+	}
+
+	log.Warnf("%s no longer exists in Netbox, removing it from the state", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+// handleDeleteNotFound is meant for the error of a Delete function's API
+// call. An object that is already gone counts as deleted.
+func handleDeleteNotFound(err error) error {
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package netbox
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testNotFoundResources lists every resource with the computed attribute
+// holding its Netbox ID.
+var testNotFoundResources = map[string]struct {
+	resource *schema.Resource
+	idKey    string
+	id       string
+	path     string
+}{
+	"netbox_ipam_prefix":      {resourceNetboxIpamPrefix(), "prefix_id", "ipam/prefix/42", "/api/ipam/prefixes/42/"},
+	"netbox_ipam_ip_address":  {resourceNetboxIpamIPAddress(), "ip_address_id", "ipam/ip-address/42", "/api/ipam/ip-addresses/42/"},
+	"netbox_ipam_aggregate":   {resourceNetboxIpamAggregate(), "aggregate_id", "ipam/aggregate/42", "/api/ipam/aggregates/42/"},
+	"netbox_ipam_vrf":         {resourceNetboxIpamVrfDomain(), "vrf_id", "ipam/vrf/42", "/api/ipam/vrfs/42/"},
+	"netbox_ipam_rir":         {resourceNetboxRegionalInternetRegistry(), "rir_id", "ipam/rir/42", "/api/ipam/rirs/42/"},
+	"netbox_org_tenant":       {resourceNetboxOrgTenant(), "tenant_id", "org/tenant/42", "/api/tenancy/tenants/42/"},
+	"netbox_org_tenant_group": {resourceNetboxOrgTenantGroup(), "tenant_group_id", "org/tenant-group/42", "/api/tenancy/tenant-groups/42/"},
+}
+
+func testNotFoundResourceData(t *testing.T, r *schema.Resource, idKey, id string) *schema.ResourceData {
+	d := r.TestResourceData()
+	d.SetId(id)
+	if idKey != "" {
+		if err := d.Set(idKey, 42); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	return d
+}
+
+func TestResourceRead_notFound(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for name, tc := range testNotFoundResources {
+		d := testNotFoundResourceData(t, tc.resource, tc.idKey, tc.id)
+
+		if err := tc.resource.Read(d, meta); err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if d.Id() != "" {
+			t.Fatalf("%s: expected ID to be cleared, got %q", name, d.Id())
+		}
+	}
+}
+
+func TestResourceDelete_notFound(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for name, tc := range testNotFoundResources {
+		d := testNotFoundResourceData(t, tc.resource, tc.idKey, tc.id)
+
+		if err := tc.resource.Delete(d, meta); err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+	}
+}
+
+func TestResourceDelete_error(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for name, tc := range testNotFoundResources {
+		server.setRoute("DELETE "+tc.path, testRoute{
+			status: http.StatusConflict,
+			body:   `{"detail": "Unable to delete object. 1 dependent objects were found"}`,
+		})
+
+		d := testNotFoundResourceData(t, tc.resource, tc.idKey, tc.id)
+
+		if err := tc.resource.Delete(d, meta); err == nil {
+			t.Fatalf("%s: expected delete error to be returned", name)
+		}
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if isNotFound(nil) {
+		t.Fatal("nil is not a 404")
+	}
+	if isNotFound(http.ErrHandlerTimeout) {
+		t.Fatal("unrelated error is not a 404")
+	}
+}
@@ -1,7 +1,12 @@
 package netbox
 
 import (
+	"fmt"
+	"net"
+	"time"
+
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -21,24 +26,107 @@ func Provider() terraform.ResourceProvider {
 func providerSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"app_id": &schema.Schema{
-			Type:        schema.TypeString,
-			Optional:    true,
-      DefaultFunc: schema.EnvDefaultFunc("NETBOX_APP_ID", nil),
-			Description: "API key used to access Netbox, generated under Admin -> Users -> Tokens and assigned to a user",
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			DefaultFunc:   schema.EnvDefaultFunc("NETBOX_APP_ID", nil),
+			ConflictsWith: []string{"app_id_file", "app_id_command"},
+			Description:   "API key used to access Netbox, generated under Admin -> Users -> Tokens and assigned to a user",
+		},
+		"app_id_file": &schema.Schema{
+			Type:          schema.TypeString,
+			Optional:      true,
+			DefaultFunc:   schema.EnvDefaultFunc("NETBOX_APP_ID_FILE", nil),
+			ConflictsWith: []string{"app_id", "app_id_command"},
+			Description:   "Path to a file holding the Netbox API key, as an alternative to app_id",
+		},
+		"app_id_command": &schema.Schema{
+			Type:          schema.TypeList,
+			Optional:      true,
+			MinItems:      1,
+			Elem:          &schema.Schema{Type: schema.TypeString},
+			ConflictsWith: []string{"app_id", "app_id_file"},
+			Description:   "Command and arguments printing the Netbox API key on stdout, as an alternative to app_id",
 		},
 		"endpoint": &schema.Schema{
 			Type:        schema.TypeString,
 			Optional:    true,
-      DefaultFunc: schema.EnvDefaultFunc("NETBOX_ENDPOINT_ADDR", nil),
+			DefaultFunc: schema.EnvDefaultFunc("NETBOX_ENDPOINT_ADDR", nil),
 			Description: "Endpoint of your Netbox instance",
 		},
-		/*
-			"timeout": &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "",
-			},
-		*/
+		"ca_cert_file": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			DefaultFunc: schema.EnvDefaultFunc("NETBOX_CA_CERT_FILE", ""),
+			Description: "Path to a PEM encoded CA bundle used to verify the Netbox server certificate",
+		},
+		"client_cert_file": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			DefaultFunc: schema.EnvDefaultFunc("NETBOX_CLIENT_CERT_FILE", ""),
+			Description: "Path to a PEM encoded client certificate presented to Netbox for mutual TLS",
+		},
+		"client_key_file": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			DefaultFunc: schema.EnvDefaultFunc("NETBOX_CLIENT_KEY_FILE", ""),
+			Description: "Path to the PEM encoded private key matching client_cert_file",
+		},
+		"insecure": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			DefaultFunc: schema.EnvDefaultFunc("NETBOX_INSECURE", false),
+			Description: "Skip verification of the Netbox server certificate",
+		},
+		"request_timeout": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			DefaultFunc:  schema.EnvDefaultFunc("NETBOX_REQUEST_TIMEOUT", "30s"),
+			ValidateFunc: validateDuration,
+			Description:  "Timeout of a single API request attempt, as a duration such as 30s",
+		},
+		"max_retries": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			DefaultFunc:  schema.EnvDefaultFunc("NETBOX_MAX_RETRIES", 3),
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Number of times a request failing with a transient error (429, 502, 503, 504, connection reset) is retried",
+		},
+		"retry_wait_min": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "1s",
+			ValidateFunc: validateDuration,
+			Description:  "Minimum time to wait before retrying a request",
+		},
+		"retry_wait_max": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "30s",
+			ValidateFunc: validateDuration,
+			Description:  "Maximum time to wait before retrying a request",
+		},
+		"rate_limit": &schema.Schema{
+			Type:         schema.TypeFloat,
+			Optional:     true,
+			DefaultFunc:  schema.EnvDefaultFunc("NETBOX_RATE_LIMIT", 0),
+			ValidateFunc: validation.FloatBetween(0, 10000),
+			Description:  "Maximum number of requests per second sent to Netbox, 0 disables the limit",
+		},
+		"max_concurrent_requests": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			DefaultFunc:  schema.EnvDefaultFunc("NETBOX_MAX_CONCURRENT_REQUESTS", 0),
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Maximum number of requests in flight against Netbox, 0 disables the limit",
+		},
+		"default_tags": &schema.Schema{
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+			Description: "Tags added to every object managed by the provider that supports tags",
+		},
 	}
 }
 
@@ -46,11 +134,18 @@ func providerSchema() map[string]*schema.Schema {
 func providerResources() map[string]*schema.Resource {
 	return map[string]*schema.Resource{
 		// IPAM
-		"netbox_ipam_rir":        resourceNetboxRegionalInternetRegistry(),
-		"netbox_ipam_vrf":        resourceNetboxIpamVrfDomain(),
-		"netbox_ipam_aggregate":  resourceNetboxIpamAggregate(),
-		"netbox_ipam_prefix":     resourceNetboxIpamPrefix(),
-		"netbox_ipam_ip_address": resourceNetboxIpamIPAddress(),
+		"netbox_ipam_rir":              resourceNetboxRegionalInternetRegistry(),
+		"netbox_ipam_vrf":              resourceNetboxIpamVrfDomain(),
+		"netbox_ipam_aggregate":        resourceNetboxIpamAggregate(),
+		"netbox_ipam_prefix":           resourceNetboxIpamPrefix(),
+		"netbox_ipam_ip_address":       resourceNetboxIpamIPAddress(),
+		"netbox_ipam_available_ip":     resourceNetboxIpamAvailableIP(),
+		"netbox_ipam_available_prefix": resourceNetboxIpamAvailablePrefix(),
+		"netbox_ipam_vlan":             resourceNetboxIpamVlan(),
+		"netbox_ipam_vlan_group":       resourceNetboxIpamVlanGroup(),
+		"netbox_ipam_role":             resourceNetboxIpamRole(),
+		"netbox_ipam_service":          resourceNetboxIpamService(),
+		"netbox_ipam_route_target":     resourceNetboxIpamRouteTarget(),
 		// Org
 		"netbox_org_tenant":       resourceNetboxOrgTenant(),
 		"netbox_org_tenant_group": resourceNetboxOrgTenantGroup(),
@@ -60,9 +155,16 @@ func providerResources() map[string]*schema.Resource {
 // List of supported data sources and their configuration fields.
 func providerDataSourcesMap() map[string]*schema.Resource {
 	return map[string]*schema.Resource{
-		"netbox_vlans":    dataSourceNetboxVlans(),
-		"netbox_prefixes": dataSourceNetboxPrefixes(),
-		"netbox_ip_address": dataSourceNetboxIPAddress(),
+		"netbox_vlans":             dataSourceNetboxVlans(),
+		"netbox_prefixes":          dataSourceNetboxPrefixes(),
+		"netbox_ip_address":        dataSourceNetboxIPAddress(),
+		"netbox_ipam_role":         dataSourceNetboxIpamRole(),
+		"netbox_ipam_services":     dataSourceNetboxIpamServices(),
+		"netbox_ipam_prefixes":     dataSourceNetboxIpamPrefixes(),
+		"netbox_ipam_ip_addresses": dataSourceNetboxIpamIPAddresses(),
+		"netbox_ipam_vlans":        dataSourceNetboxIpamVlans(),
+		"netbox_org_tenant":        dataSourceNetboxOrgTenant(),
+		"netbox_org_tenant_group":  dataSourceNetboxOrgTenantGroup(),
 	}
 }
 
@@ -71,9 +173,92 @@ func providerDataSourcesMap() map[string]*schema.Resource {
 // interacts with the API.
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
-		AppID:    d.Get("app_id").(string),
-		Endpoint: d.Get("endpoint").(string),
-		//Timeout:  d.Get("timeout").(string),
+		AppID:     d.Get("app_id").(string),
+		AppIDFile: d.Get("app_id_file").(string),
+		Endpoint:  d.Get("endpoint").(string),
+
+		CACertFile:     d.Get("ca_cert_file").(string),
+		ClientCertFile: d.Get("client_cert_file").(string),
+		ClientKeyFile:  d.Get("client_key_file").(string),
+		Insecure:       d.Get("insecure").(bool),
+
+		MaxRetries: d.Get("max_retries").(int),
+
+		RateLimit:             d.Get("rate_limit").(float64),
+		MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+	}
+
+	for _, tag := range d.Get("default_tags").(*schema.Set).List() {
+		config.DefaultTags = append(config.DefaultTags, tag.(string))
 	}
+
+	for _, arg := range d.Get("app_id_command").([]interface{}) {
+		config.AppIDCommand = append(config.AppIDCommand, arg.(string))
+	}
+
+	// Durations were validated at plan time.
+	config.RequestTimeout, _ = time.ParseDuration(d.Get("request_timeout").(string))
+	config.RetryWaitMin, _ = time.ParseDuration(d.Get("retry_wait_min").(string))
+	config.RetryWaitMax, _ = time.ParseDuration(d.Get("retry_wait_max").(string))
+
+	if config.RetryWaitMax < config.RetryWaitMin {
+		return nil, fmt.Errorf("retry_wait_max (%s) must not be lower than retry_wait_min (%s)", config.RetryWaitMax, config.RetryWaitMin)
+	}
+
 	return config.Client()
 }
+
+// validateDuration checks that a string attribute parses as a positive Go duration.
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	d, err := time.ParseDuration(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
+	} else if d <= 0 {
+		errors = append(errors, fmt.Errorf("%q must be a positive duration, got %s", k, d))
+	}
+	return
+}
+
+// validateDate checks that a string attribute is an ISO 8601 date such as
+// 2019-09-01.
+func validateDate(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.Parse("2006-01-02", v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a date as YYYY-MM-DD, got %q", k, v))
+	}
+	return
+}
+
+// validateCIDRPrefix checks that a string attribute is a prefix in CIDR
+// notation, such as 10.0.0.0/24, with no host bits set.
+func validateCIDRPrefix(v interface{}, k string) (ws []string, errors []error) {
+	ip, ipNet, err := net.ParseCIDR(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a prefix in CIDR notation, such as 10.0.0.0/24, got %q", k, v))
+	} else if !ip.Equal(ipNet.IP) {
+		errors = append(errors, fmt.Errorf("%q has host bits set in %q, did you mean %s?", k, v, ipNet))
+	}
+	return
+}
+
+// validateCIDRAddress checks that a string attribute is an IP address with its
+// mask in CIDR notation, such as 10.0.0.1/24.
+func validateCIDRAddress(v interface{}, k string) (ws []string, errors []error) {
+	if _, _, err := net.ParseCIDR(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be an IP address with its mask, such as 10.0.0.1/24, got %q", k, v))
+	}
+	return
+}
+
+// suppressEquivalentCIDR is a schema.SchemaDiffSuppressFunc ignoring changes
+// between two spellings of the same address or prefix, such as 2001:DB8::/32
+// and 2001:db8::/32. Netbox stores the canonical form, so without it the
+// state never matches such a configuration. This cannot be a CustomizeDiff,
+// which may only change computed attributes.
+func suppressEquivalentCIDR(k, old, new string, d *schema.ResourceData) bool {
+	oldIP, oldNet, err := net.ParseCIDR(old)
+	if err != nil {
+		return false
+	}
+	newIP, newNet, err := net.ParseCIDR(new)
+	return err == nil && oldIP.Equal(newIP) && oldNet.String() == newNet.String()
+}
@@ -0,0 +1,152 @@
+package netbox
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// clock abstracts time so the limiter can be tested without sleeping.
+type clock interface {
+	Now() time.Time
+	// After returns a channel that fires once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// tokenBucket is a token bucket refilled at rate tokens per second up to
+// burst tokens. Callers reserve a token and are told how long to wait for it,
+// so concurrent callers are queued fairly in reservation order.
+type tokenBucket struct {
+	mu     sync.Mutex
+	clock  clock
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, c clock) *tokenBucket {
+	return &tokenBucket{
+		clock:  c,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   c.Now(),
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel hands back a token reserved by a caller that gave up waiting.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// requestLimiter caps the request rate and the number of requests in flight
+// against Netbox. A nil requestLimiter imposes no limits.
+type requestLimiter struct {
+	clock    clock
+	bucket   *tokenBucket
+	inFlight chan struct{}
+}
+
+// newRequestLimiter returns a limiter allowing rate requests per second and
+// maxInFlight concurrent requests. Zero disables the respective limit, and nil
+// is returned when both are disabled.
+func newRequestLimiter(rate float64, maxInFlight int, c clock) *requestLimiter {
+	if rate <= 0 && maxInFlight <= 0 {
+		return nil
+	}
+
+	l := &requestLimiter{clock: c}
+
+	if rate > 0 {
+		l.bucket = newTokenBucket(rate, int(math.Max(1, math.Ceil(rate))), c)
+	}
+
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+
+	return l
+}
+
+// acquire blocks until a request may be sent, returning a function that must
+// be called once the request is complete.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.inFlight != nil {
+			<-l.inFlight
+		}
+	}
+
+	if l.bucket != nil {
+		if wait := l.bucket.reserve(); wait > 0 {
+			select {
+			case <-l.clock.After(wait):
+			case <-ctx.Done():
+				l.bucket.cancel()
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	var once sync.Once
+
+	return func() { once.Do(release) }, nil
+}
+
+// releaseOnClose frees the in-flight slot held by a request once its response
+// body has been consumed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
@@ -0,0 +1,196 @@
+package netbox
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is a manually advanced clock. Timers returned by After fire as
+// soon as the clock is advanced past their deadline.
+type testClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []testTimer
+}
+
+type testTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Unix(1500000000, 0)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, testTimer{at: c.now.Add(d), c: ch})
+	return ch
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+}
+
+func TestTokenBucket_reserve(t *testing.T) {
+	clk := newTestClock()
+	b := newTokenBucket(2, 2, clk)
+
+	// The initial burst is available straight away.
+	for i := 0; i < 2; i++ {
+		if wait := b.reserve(); wait != 0 {
+			t.Fatalf("reservation %d: expected no wait, got %s", i, wait)
+		}
+	}
+
+	// Further reservations queue up at 2 per second.
+	if wait := b.reserve(); wait != 500*time.Millisecond {
+		t.Fatalf("expected 500ms, got %s", wait)
+	}
+	if wait := b.reserve(); wait != time.Second {
+		t.Fatalf("expected 1s, got %s", wait)
+	}
+
+	// After the queue drains and the bucket refills, the burst is available
+	// again but never exceeded.
+	clk.Advance(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		if wait := b.reserve(); wait != 0 {
+			t.Fatalf("reservation %d after refill: expected no wait, got %s", i, wait)
+		}
+	}
+	if wait := b.reserve(); wait != 500*time.Millisecond {
+		t.Fatalf("expected 500ms, got %s", wait)
+	}
+}
+
+func TestRequestLimiter_disabled(t *testing.T) {
+	if l := newRequestLimiter(0, 0, newTestClock()); l != nil {
+		t.Fatalf("expected nil limiter, got %v", l)
+	}
+
+	var l *requestLimiter
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	release()
+}
+
+func TestRequestLimiter_rate(t *testing.T) {
+	clk := newTestClock()
+	l := newRequestLimiter(1, 0, clk)
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	release()
+
+	done := make(chan struct{})
+	go func() {
+		release, _ := l.acquire(context.Background())
+		release()
+		close(done)
+	}()
+
+	testWaitForTimers(t, clk, 1)
+
+	select {
+	case <-done:
+		t.Fatal("second request was not rate limited")
+	default:
+	}
+
+	clk.Advance(time.Second)
+	<-done
+}
+
+func TestRequestLimiter_maxInFlight(t *testing.T) {
+	l := newRequestLimiter(0, 1, newTestClock())
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.acquire(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded while slot is held, got %v", err)
+	}
+
+	// Releasing twice must not free more slots than were taken.
+	release()
+	release()
+
+	if _, err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+
+	if _, err := l.acquire(ctx2); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded while slot is held, got %v", err)
+	}
+}
+
+// testWaitForTimers blocks until n timers are pending on the clock.
+func testWaitForTimers(t *testing.T, clk *testClock, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		clk.mu.Lock()
+		pending := len(clk.timers)
+		clk.mu.Unlock()
+		if pending >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d timers", n)
+}
+
+func TestRetryTransport_releasesLimiterSlot(t *testing.T) {
+	l := newRequestLimiter(0, 1, newTestClock())
+	next := &testRoundTripper{statuses: []int{503, 200, 200}}
+	rt := newRetryTransport(next, time.Second, 1, time.Millisecond, time.Millisecond, l)
+	rt.sleep = func(context.Context, time.Duration) error { return nil }
+
+	for i := 0; i < 2; i++ {
+		resp, err := rt.RoundTrip(testRetryRequest(t, "GET", ""))
+		if err != nil {
+			t.Fatalf("request %d: err: %s", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	if len(next.bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(next.bodies))
+	}
+}
@@ -0,0 +1,85 @@
+package netbox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Every resource and data source has an ID of the form "<group>/<kind>/<id>",
+// where group is the resource name prefix (ipam, org), kind the Netbox object
+// type and id its Netbox ID: "ipam/prefix/42", "ipam/ip-address/10",
+// "org/tenant-group/2". Resources managing the same objects share a kind, a
+// netbox_ipam_available_ip is an "ipam/ip-address".
+//
+// The Netbox ID is always taken from the resource ID. The *_id attributes,
+// such as prefix_id, only repeat it for use in other resources.
+
+// netboxID returns the Netbox ID held by the ID of a resource of the given
+// kind, such as "ipam/prefix".
+func netboxID(d *schema.ResourceData, kind string) (int64, error) {
+	raw := d.Id()
+	if !strings.HasPrefix(raw, kind+"/") {
+		return 0, fmt.Errorf("invalid resource ID %q, expected %s/<id>", raw, kind)
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(raw, kind+"/"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resource ID %q, expected %s/<id>", raw, kind)
+	}
+
+	return id, nil
+}
+
+// upgradeResourceID adds to r the state upgrade rewriting the IDs of older
+// states, a bare Netbox ID or anything but "<kind>/<id>", into the current
+// form, and returns r. The schema itself is unchanged by this upgrade, so the
+// state it decodes has the current schema.
+func upgradeResourceID(r *schema.Resource, kind, idKey string) *schema.Resource {
+	r.StateUpgraders = append(r.StateUpgraders, schema.StateUpgrader{
+		Version: r.SchemaVersion,
+		Type:    r.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			return upgradeResourceIDState(rawState, kind, idKey)
+		},
+	})
+	r.SchemaVersion++
+
+	return r
+}
+
+// upgradeResourceIDState rewrites the ID of a state into "<kind>/<id>". The
+// Netbox ID is read from the ID itself, or from the idKey attribute when the
+// ID does not hold one.
+func upgradeResourceIDState(rawState map[string]interface{}, kind, idKey string) (map[string]interface{}, error) {
+	raw, _ := rawState["id"].(string)
+	if strings.HasPrefix(raw, kind+"/") {
+		return rawState, nil
+	}
+
+	id, err := strconv.ParseInt(raw[strings.LastIndex(raw, "/")+1:], 10, 64)
+	if err != nil || id == 0 {
+		switch value := rawState[idKey].(type) {
+		case float64:
+			id = int64(value)
+		case int:
+			id = int64(value)
+		}
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("unable to upgrade resource ID %q, expected %s/<id>", raw, kind)
+	}
+
+	log.Debugf("Upgrading resource ID %q to %s/%d", raw, kind, id)
+
+	rawState["id"] = fmt.Sprintf("%s/%d", kind, id)
+	if idKey != "" {
+		rawState[idKey] = id
+	}
+
+	return rawState, nil
+}
@@ -0,0 +1,169 @@
+package netbox
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/config/hcl2shim"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testUpgradeState upgrades a recorded state the way Terraform does: a
+// flatmap state, written by Terraform 0.11, is decoded with the schema of its
+// version, then every state upgrader from that version is applied.
+func testUpgradeState(t *testing.T, r *schema.Resource, version int, flatmap map[string]string, rawState map[string]interface{}) map[string]interface{} {
+	if flatmap != nil {
+		ty := r.CoreConfigSchema().ImpliedType()
+		for _, upgrader := range r.StateUpgraders {
+			if upgrader.Version == version {
+				ty = upgrader.Type
+			}
+		}
+
+		value, err := hcl2shim.HCL2ValueFromFlatmap(flatmap, ty)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		rawState, err = schema.StateValueToJSONMap(value, ty)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	for _, upgrader := range r.StateUpgraders {
+		if upgrader.Version != version {
+			continue
+		}
+
+		var err error
+		rawState, err = upgrader.Upgrade(rawState, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		version++
+	}
+
+	if version != r.SchemaVersion {
+		t.Fatalf("state upgraded to version %d, expected %d", version, r.SchemaVersion)
+	}
+
+	return rawState
+}
+
+func TestResourceID_stateUpgrade(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "state", "*.json"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("no state fixtures")
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			var tc struct {
+				Resource      string                 `json:"resource"`
+				SchemaVersion int                    `json:"schema_version"`
+				Flatmap       map[string]string      `json:"flatmap"`
+				Attributes    map[string]interface{} `json:"attributes"`
+				Config        map[string]interface{} `json:"config"`
+				ID            string                 `json:"id"`
+			}
+			if err := json.Unmarshal([]byte(testFixture(t, "state", filepath.Base(fixture))), &tc); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			r := providerResources()[tc.Resource]
+
+			rawState := testUpgradeState(t, r, tc.SchemaVersion, tc.Flatmap, tc.Attributes)
+
+			value, err := schema.JSONMapToStateValue(rawState, r.CoreConfigSchema())
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			state, err := r.ShimInstanceStateFromValue(value)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if state.ID != tc.ID {
+				t.Fatalf("expected ID %q, got %q", tc.ID, state.ID)
+			}
+
+			// Read takes the Netbox ID from the upgraded resource ID.
+			if _, err := netboxID(r.Data(state), filepath.Dir(tc.ID)); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			// The unchanged configuration must plan no change, let alone a
+			// replacement.
+			config, err := config.NewRawConfig(tc.Config)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			diff, err := r.Diff(state, terraform.NewResourceConfig(config), nil)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if diff != nil && (diff.RequiresNew() || !diff.Empty()) {
+				t.Fatalf("expected no diff, got %v", diff)
+			}
+		})
+	}
+}
+
+func TestUpgradeResourceIDState(t *testing.T) {
+	cases := []struct {
+		rawState map[string]interface{}
+		id       string
+		err      string
+	}{
+		{map[string]interface{}{"id": "ipam/vlan/9", "vlan_id": float64(9)}, "ipam/vlan/9", ""},
+		{map[string]interface{}{"id": "9", "vlan_id": float64(0)}, "ipam/vlan/9", ""},
+		{map[string]interface{}{"id": "VLAN-16", "vlan_id": float64(9)}, "ipam/vlan/9", ""},
+		{map[string]interface{}{"id": "VLAN-16", "vlan_id": float64(0)}, "", `unable to upgrade resource ID "VLAN-16", expected ipam/vlan/<id>`},
+	}
+
+	for _, tc := range cases {
+		actual, err := upgradeResourceIDState(tc.rawState, "ipam/vlan", "vlan_id")
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%v: expected error %q, got %v", tc.rawState, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: err: %s", tc.rawState, err)
+		}
+		if actual["id"] != tc.id {
+			t.Errorf("expected ID %q, got %v", tc.id, actual["id"])
+		}
+	}
+}
+
+func TestNetboxID(t *testing.T) {
+	d := resourceNetboxIpamVlan().TestResourceData()
+
+	for raw, expected := range map[string]int64{
+		"ipam/vlan/9":       9,
+		"9":                 0,
+		"ipam/vlan-group/9": 0,
+		"ipam/vlan/":        0,
+	} {
+		d.SetId(raw)
+
+		id, err := netboxID(d, "ipam/vlan")
+		if expected == 0 {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %d", raw, id)
+			}
+			continue
+		}
+		if err != nil || id != expected {
+			t.Errorf("%q: expected %d, got %d (%v)", raw, expected, id, err)
+		}
+	}
+}
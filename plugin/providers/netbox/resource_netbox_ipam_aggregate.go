@@ -1,10 +1,12 @@
 package netbox
 
 import (
-	"strconv"
+	"fmt"
+	"net/http"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/tpretz/go-netbox/netbox/client"
 	"github.com/tpretz/go-netbox/netbox/client/ipam"
 	"github.com/tpretz/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -12,148 +14,203 @@ import (
 
 // resourceNetboxIpamAggregate is the core Terraform resource structure for the netbox_ipam_aggregate resource.
 func resourceNetboxIpamAggregate() *schema.Resource {
-	return &schema.Resource{
+	return upgradeResourceID(&schema.Resource{
 		Create: resourceNetboxIpamAggregateCreate,
 		Read:   resourceNetboxIpamAggregateRead,
 		Update: resourceNetboxIpamAggregateUpdate,
 		Delete: resourceNetboxIpamAggregateDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNetboxIpamAggregateImport,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"aggregate_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
 			"prefix": &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Network prefix in slash notation for this aggregate. Example: 192.168.10.0/24.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validateCIDRPrefix,
+				DiffSuppressFunc: suppressEquivalentCIDR,
+				Description:      "Network prefix in slash notation for this aggregate. Example: 192.168.10.0/24.",
 			},
 			"rir_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"rir_slug"},
+				Description:   "Netbox ID of the regional internet registry (RIR) that manages this prefix.",
+			},
+			"rir_slug": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"rir_id"},
+				Description:   "Slug of the regional internet registry (RIR) that manages this prefix, instead of rir_id.",
+			},
+			"tenant_id": &schema.Schema{
 				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "Netbox ID of the regional internet registry (RIR) that manages this prefix.",
+				Optional:    true,
+				Description: "Netbox ID of the tenant of this aggregate, Netbox 2.10 and later.",
+			},
+			"date_added": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDate,
+				Description:  "Date this aggregate was added, as YYYY-MM-DD.",
 			},
 			"description": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "Description of this aggregate.",
 			},
-			/*
-				"date_added": &schema.Schema{
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Date this aggregate was added.",
-				},
-			*/
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
 		},
-	}
+	}, "ipam/aggregate", "aggregate_id")
+}
+
+// aggregatePayload is an aggregate as returned by Netbox. The tenant is only
+// returned by Netbox 2.10 and later.
+type aggregatePayload struct {
+	ID           int64                `json:"id"`
+	Prefix       *string              `json:"prefix"`
+	Rir          *models.NestedRIR    `json:"rir"`
+	Tenant       *models.NestedTenant `json:"tenant"`
+	DateAdded    *string              `json:"date_added"`
+	Description  string               `json:"description"`
+	Tags         []string             `json:"tags"`
+	CustomFields interface{}          `json:"custom_fields"`
 }
 
 // resourceNetboxIpamAggregateCreate creates a new aggregate in Netbox.
 func resourceNetboxIpamAggregateCreate(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
-
-	prefix := d.Get("prefix").(string)
-	rirID := int64(d.Get("rir_id").(int))
-	description := d.Get("description").(string)
-	// TODO dateAdded
-	tags := []string{}
-
-	var parm = ipam.NewIPAMAggregatesCreateParams().WithData(
-		&models.AggregateCreateUpdate{
-			Prefix:      &prefix,
-			Description: description,
-			Rir:         &rirID,
-			Tags:        tags,
-			// TODO DateAdded
-		},
-	)
+	c := meta.(*ProviderNetboxClient)
 
-	log.Debugf("Executing IPAMAggregatesCreate against Netbox: %v", parm)
+	body, err := aggregateBody(d, meta)
+	if err != nil {
+		return err
+	}
 
-	out, err := netboxClient.IPAM.IPAMAggregatesCreate(parm, nil)
+	log.Debugf("Creating aggregate in Netbox: %v", body)
 
-	if err != nil {
-		log.Debugf("Failed to execute IPAMAggregatesCreate: %v", err)
+	var out aggregatePayload
+	if err := c.doJSON(http.MethodPost, "/ipam/aggregates/", body, &out); err != nil {
+		log.Debugf("Failed to create aggregate: %v", err)
 
 		return err
 	}
 
-	// TODO Probably a better way to parse this ID
-	d.SetId(strconv.Itoa(int(out.Payload.ID)))
+	d.SetId(fmt.Sprintf("ipam/aggregate/%d", out.ID))
+	if err := d.Set("aggregate_id", out.ID); err != nil {
+		return err
+	}
 
-	log.Debugf("Done Executing IPAMAggregatesCreate: %v", out)
+	log.Debugf("Done creating aggregate %d", out.ID)
 
 	return nil
 }
 
 // resourceNetboxIpamAggregateUpdate applies updates to an aggregate by ID when deltas are detected by Terraform.
 func resourceNetboxIpamAggregateUpdate(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
-
-	id, err := strconv.Atoi(d.Id())
+	c := meta.(*ProviderNetboxClient)
 
+	id, err := netboxID(d, "ipam/aggregate")
 	if err != nil {
-		log.Debugf("Error parsing Aggregate ID %v = %v", d.Id(), err)
 		return err
 	}
 
-	prefix := d.Get("prefix").(string)
-	rirID := int64(d.Get("rir_id").(int))
-	description := d.Get("description").(string)
-	// TODO dateAdded
-	tags := []string{}
-
-	var parm = ipam.NewIPAMAggregatesUpdateParams().WithID(int64(id)).WithData(
-		&models.AggregateCreateUpdate{
-			Prefix:      &prefix,
-			Description: description,
-			Rir:         &rirID,
-			Tags:        tags,
-			// TODO DateAdded
-		},
-	)
-
-	log.Debugf("Executing IPAMAggregatesUpdate against Netbox: %v", parm)
+	body, err := aggregateBody(d, meta)
+	if err != nil {
+		return err
+	}
 
-	out, err := netboxClient.IPAM.IPAMAggregatesUpdate(parm, nil)
+	log.Debugf("Updating aggregate %d in Netbox: %v", id, body)
 
-	if err != nil {
-		log.Debugf("Failed to execute IPAMAggregatesUpdate: %v", err)
+	if err := c.doJSON(http.MethodPut, fmt.Sprintf("/ipam/aggregates/%d/", id), body, nil); err != nil {
+		log.Debugf("Failed to update aggregate %d: %v", id, err)
 
 		return err
 	}
 
-	log.Debugf("Done Executing IPAMAggregatesUpdate: %v", out)
+	log.Debugf("Done updating aggregate %d", id)
 
 	return nil
 }
 
 // resourceNetboxIpamAggregateRead reads an existing aggregate by ID.
 func resourceNetboxIpamAggregateRead(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
-
-	id, err := strconv.Atoi(d.Id())
+	c := meta.(*ProviderNetboxClient)
 
+	id, err := netboxID(d, "ipam/aggregate")
 	if err != nil {
-		log.Debugf("Error parsing aggregate ID %v = %v", d.Id(), err)
 		return err
 	}
 
-	var readParams = ipam.NewIPAMAggregatesReadParams().WithID(int64(id))
+	var aggregate aggregatePayload
 
-	readResult, err := netboxClient.IPAM.IPAMAggregatesRead(readParams, nil)
+	err = c.doJSON(http.MethodGet, fmt.Sprintf("/ipam/aggregates/%d/", id), nil, &aggregate)
 
 	if err != nil {
 		log.Debugf("Error fetching aggregate ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("aggregate_id", aggregate.ID); err != nil {
 		return err
 	}
 
-	d.Set("prefix", readResult.Payload.Prefix)
-	d.Set("rir_id", readResult.Payload.Rir.ID)
-	d.Set("description", readResult.Payload.Description)
-	// TODO date_created
+	var prefix string
+	if aggregate.Prefix != nil {
+		prefix = *aggregate.Prefix
+	}
+	if err := d.Set("prefix", prefix); err != nil {
+		return err
+	}
 
-	log.Debugf("Read Aggregate %d from Netbox = %v", readResult.Payload.Rir.ID, readResult.Payload)
+	var rirID int64
+	var rirSlug string
+	if aggregate.Rir != nil {
+		rirID = aggregate.Rir.ID
+		if aggregate.Rir.Slug != nil {
+			rirSlug = *aggregate.Rir.Slug
+		}
+	}
+	if err := d.Set("rir_id", rirID); err != nil {
+		return err
+	}
+	if err := d.Set("rir_slug", rirSlug); err != nil {
+		return err
+	}
+
+	var tenantID int64
+	if aggregate.Tenant != nil {
+		tenantID = aggregate.Tenant.ID
+	}
+	if err := d.Set("tenant_id", tenantID); err != nil {
+		return err
+	}
+
+	var dateAdded string
+	if aggregate.DateAdded != nil {
+		dateAdded = *aggregate.DateAdded
+	}
+	if err := d.Set("date_added", dateAdded); err != nil {
+		return err
+	}
+
+	if err := d.Set("description", aggregate.Description); err != nil {
+		return err
+	}
+	if err := d.Set("tags", flattenTags(d, meta, aggregate.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, aggregate.CustomFields)); err != nil {
+		return err
+	}
+
+	log.Debugf("Read Aggregate %d from Netbox = %v", id, aggregate)
 
 	return nil
 }
@@ -162,14 +219,12 @@ func resourceNetboxIpamAggregateRead(d *schema.ResourceData, meta interface{}) e
 func resourceNetboxIpamAggregateDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Debugf("Deleting Aggregate: %v\n", d)
 
-	id, err := strconv.Atoi(d.Id())
-
+	id, err := netboxID(d, "ipam/aggregate")
 	if err != nil {
-		log.Debugf("Error parsing Aggregate ID %v = %v", d.Id(), err)
 		return err
 	}
 
-	var deleteParameters = ipam.NewIPAMAggregatesDeleteParams().WithID(int64(id))
+	var deleteParameters = ipam.NewIPAMAggregatesDeleteParams().WithID(id)
 
 	c := meta.(*ProviderNetboxClient).client
 
@@ -177,9 +232,110 @@ func resourceNetboxIpamAggregateDelete(d *schema.ResourceData, meta interface{})
 
 	if err != nil {
 		log.Debugf("Failed to execute IPAMAggregatesDelete: %v", err)
+
+		return handleDeleteNotFound(err)
 	}
 
 	log.Debugf("Done Executing IPAMAggregatesDelete: %v", out)
 
 	return nil
 }
+
+// resourceNetboxIpamAggregateImport imports an aggregate by ID, either "2"
+// or "ipam/aggregate/2", or by prefix.
+func resourceNetboxIpamAggregateImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/aggregate")
+	if !ok {
+		var err error
+		id, err = aggregateIDByPrefix(meta.(*ProviderNetboxClient).client, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importedState(d, "ipam/aggregate", "aggregate_id", id)
+}
+
+// aggregateRirID returns the ID of the aggregate's RIR, looking it up by slug
+// when rir_slug is set instead of rir_id.
+func aggregateRirID(d *schema.ResourceData, meta interface{}) (int64, error) {
+	if d.HasChange("rir_slug") || d.Get("rir_id").(int) == 0 {
+		if slug := d.Get("rir_slug").(string); slug != "" {
+			return rirIDBySlug(meta.(*ProviderNetboxClient).client, slug)
+		}
+	}
+
+	if id := d.Get("rir_id").(int); id != 0 {
+		return int64(id), nil
+	}
+
+	return 0, fmt.Errorf("one of rir_id or rir_slug must be set")
+}
+
+// rirIDBySlug returns the ID of the RIR with the given slug.
+func rirIDBySlug(netboxClient *client.NetBox, slug string) (int64, error) {
+	param := ipam.NewIPAMRirsListParams()
+	param.SetSlug(&slug)
+
+	limit := int64(2)
+	param.SetLimit(&limit)
+
+	log.Debugf("Executing IPAMRirsList against Netbox: %v", param)
+
+	out, err := netboxClient.IPAM.IPAMRirsList(param, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMRirsList: %v", err)
+
+		return 0, err
+	}
+
+	if len(out.Payload.Results) != 1 {
+		return 0, fmt.Errorf("no RIR matches slug %q", slug)
+	}
+
+	return out.Payload.Results[0].ID, nil
+}
+
+// aggregateBody returns the payload of an aggregate create or update request.
+func aggregateBody(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	c := meta.(*ProviderNetboxClient)
+
+	rirID, err := aggregateRirID(d, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"prefix":      d.Get("prefix").(string),
+		"rir":         rirID,
+		"date_added":  nil,
+		"description": d.Get("description").(string),
+		"tags":        expandTags(d, meta),
+	}
+
+	if dateAdded := d.Get("date_added").(string); dateAdded != "" {
+		body["date_added"] = dateAdded
+	}
+
+	// Older servers have no tenant field, it is only sent when supported so
+	// they do not reject the request.
+	if tenantID := d.Get("tenant_id").(int); tenantID != 0 {
+		if err := c.requireFeature(featureAggregateTenant, "tenant_id"); err != nil {
+			return nil, err
+		}
+		body["tenant"] = tenantID
+	} else if featureAggregateTenant.supportedBy(c.apiVersion()) {
+		body["tenant"] = nil
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	if customFields != nil {
+		body["custom_fields"] = customFields
+	}
+
+	return body, nil
+}
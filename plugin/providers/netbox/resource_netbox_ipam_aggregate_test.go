@@ -0,0 +1,152 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamAggregate_create(t *testing.T) {
+	cases := []struct {
+		version  string
+		raw      map[string]interface{}
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			version: "2.10",
+			raw:     map[string]interface{}{"rir_id": 1, "tenant_id": 7, "date_added": "2019-09-01"},
+			expected: map[string]interface{}{
+				"prefix":      "192.168.0.0/16",
+				"rir":         float64(1),
+				"tenant":      float64(7),
+				"date_added":  "2019-09-01",
+				"description": "",
+				"tags":        []interface{}{},
+			},
+		},
+		{
+			version: "2.5",
+			raw:     map[string]interface{}{"rir_slug": "squidland"},
+			expected: map[string]interface{}{
+				"prefix":      "192.168.0.0/16",
+				"rir":         float64(1),
+				"date_added":  nil,
+				"description": "",
+				"tags":        []interface{}{},
+			},
+		},
+		{
+			version: "2.9",
+			raw:     map[string]interface{}{"rir_id": 1, "tenant_id": 7},
+			err:     "tenant_id requires Netbox API >= 2.10 (aggregate tenants), but the server runs Netbox API 2.9",
+		},
+		{
+			version: "2.5",
+			raw:     map[string]interface{}{"rir_slug": "inkopolis"},
+			err:     `no RIR matches slug "inkopolis"`,
+		},
+		{
+			version: "2.5",
+			raw:     map[string]interface{}{},
+			err:     "one of rir_id or rir_slug must be set",
+		},
+	}
+
+	for _, tc := range cases {
+		server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+			"GET /api/ipam/rirs/?limit=2&slug=squidland": {body: `{"count": 1, "results": [{"id": 1, "name": "Squidland", "slug": "squidland"}]}`},
+			"GET /api/ipam/rirs/?limit=2&slug=inkopolis": {body: `{"count": 0, "results": []}`},
+			"POST /api/ipam/aggregates/":                 {status: http.StatusCreated, body: `{"id": 2}`},
+		})
+
+		raw := map[string]interface{}{"prefix": "192.168.0.0/16"}
+		for name, value := range tc.raw {
+			raw[name] = value
+		}
+
+		d := schema.TestResourceDataRaw(t, resourceNetboxIpamAggregate().Schema, raw)
+
+		err := resourceNetboxIpamAggregateCreate(d, server.meta(t))
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("%v: expected error %q, got %v", tc.raw, tc.err, err)
+			}
+			server.Close()
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: err: %s", tc.raw, err)
+		}
+		if d.Id() != "ipam/aggregate/2" || d.Get("aggregate_id") != 2 {
+			t.Fatalf("%v: unexpected ID %q", tc.raw, d.Id())
+		}
+
+		requests := server.received()
+		var sent map[string]interface{}
+		if err := json.Unmarshal([]byte(requests[len(requests)-1].body), &sent); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(sent, tc.expected) {
+			t.Fatalf("%v: expected %v to be sent, got %v", tc.raw, tc.expected, sent)
+		}
+
+		server.Close()
+	}
+}
+
+func TestResourceNetboxIpamAggregate_dateAdded(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"2019-09-01":           true,
+		"2019-13-01":           false,
+		"01/09/2019":           false,
+		"2019-09-01T08:12:44Z": false,
+	} {
+		_, errs := validateDate(value, "date_added")
+		if (len(errs) == 0) != valid {
+			t.Fatalf("%q: expected valid to be %v, got errors %v", value, valid, errs)
+		}
+	}
+}
+
+func TestResourceNetboxIpamAggregate_prefix(t *testing.T) {
+	s := resourceNetboxIpamAggregate().Schema["prefix"]
+
+	for prefix, valid := range map[string]bool{
+		"10.0.0.0/8":     true,
+		"2001:DB8::/32":  true,
+		"10.0.0.1/8":     false,
+		"2001:db8::1/32": false,
+		"10.0.0.0":       false,
+		"10.0.0.0/8 ":    false,
+	} {
+		_, errs := s.ValidateFunc(prefix, "prefix")
+		if (len(errs) == 0) != valid {
+			t.Fatalf("%q: expected valid to be %v, got errors %v", prefix, valid, errs)
+		}
+	}
+
+	if !s.DiffSuppressFunc("prefix", "2001:db8::/32", "2001:DB8::/32", nil) {
+		t.Errorf("expected 2001:db8::/32 and 2001:DB8::/32 to be equivalent")
+	}
+}
+
+func TestResourceNetboxIpamAggregate_readPartial(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/aggregates/2/": {body: `{"id": 2, "prefix": "192.168.0.0/16", "rir": null, "date_added": null}`},
+	})
+	defer server.Close()
+
+	d := resourceNetboxIpamAggregate().TestResourceData()
+	d.SetId("ipam/aggregate/2")
+
+	if err := resourceNetboxIpamAggregateRead(d, server.meta(t)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("prefix") != "192.168.0.0/16" || d.Get("rir_id") != 0 || d.Get("rir_slug") != "" || d.Get("date_added") != "" {
+		t.Fatalf("unexpected state %v", d.State())
+	}
+}
@@ -0,0 +1,332 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceNetboxIpamAvailableIP is the core Terraform resource structure for the netbox_ipam_available_ip resource.
+//
+// The address is allocated once from the free addresses of the prefix; the
+// other attributes are updated in place like a netbox_ipam_ip_address.
+func resourceNetboxIpamAvailableIP() *schema.Resource {
+	return upgradeResourceID(&schema.Resource{
+		Create: resourceNetboxIpamAvailableIPCreate,
+		Read:   resourceNetboxIpamAvailableIPRead,
+		Update: resourceNetboxIpamAvailableIPUpdate,
+		Delete: resourceNetboxIpamIPAddressDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetboxIpamAvailableIPImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"prefix_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the prefix to allocate the next free address from.",
+			},
+			"ip_address_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"address": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The allocated address, in CIDR notation.",
+			},
+			"vrf_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "VRF of the address, the VRF of the prefix when not set.",
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"status": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "active",
+				ValidateFunc:     ipAddressStatusChoices.validate,
+				DiffSuppressFunc: ipAddressStatusChoices.suppressEquivalent,
+			},
+			"dns_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
+		},
+	}, "ipam/ip-address", "ip_address_id")
+}
+
+// resourceNetboxIpamAvailableIPCreate allocates the next free address of a prefix in Netbox.
+func resourceNetboxIpamAvailableIPCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	prefixID := d.Get("prefix_id").(int)
+
+	body, err := availableIPBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// Netbox hands out the same address to concurrent requests.
+	lockKey := allocationLockKey("ipam/prefix", prefixID)
+	netboxMutexKV.Lock(lockKey)
+	defer netboxMutexKV.Unlock(lockKey)
+
+	log.Debugf("Allocating an IP address from prefix %d: %v", prefixID, body)
+
+	var out ipAddressPayload
+	err = c.doJSON(http.MethodPost, fmt.Sprintf("/ipam/prefixes/%d/available-ips/", prefixID), body, &out)
+
+	if err != nil {
+		log.Debugf("Failed to allocate an IP address from prefix %d: %v", prefixID, err)
+
+		if isNotFound(err) {
+			return fmt.Errorf("prefix %d does not exist", prefixID)
+		}
+		if isConflict(err) {
+			return fmt.Errorf("prefix %d has no free IP address left", prefixID)
+		}
+		return err
+	}
+
+	// Netbox 2.5 answers 204 No Content when the prefix is full.
+	if out.ID == 0 || out.Address == nil {
+		return fmt.Errorf("prefix %d has no free IP address left", prefixID)
+	}
+
+	d.SetId(fmt.Sprintf("ipam/ip-address/%d", out.ID))
+	if err := d.Set("ip_address_id", out.ID); err != nil {
+		return err
+	}
+	if err := d.Set("address", out.Address); err != nil {
+		return err
+	}
+
+	log.Debugf("Allocated IP address %s from prefix %d", *out.Address, prefixID)
+
+	// Older Netbox versions always give the address the VRF of the prefix.
+	vrfID := int64(d.Get("vrf_id").(int))
+	if vrfID != 0 && (out.Vrf == nil || out.Vrf.ID != vrfID) {
+		return resourceNetboxIpamAvailableIPUpdate(d, meta)
+	}
+
+	return nil
+}
+
+// resourceNetboxIpamAvailableIPUpdate applies updates to an allocated IP Address by ID when deltas are detected by Terraform.
+func resourceNetboxIpamAvailableIPUpdate(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	id, err := netboxID(d, "ipam/ip-address")
+	if err != nil {
+		return err
+	}
+
+	address := d.Get("address").(string)
+	vrfID := int64(d.Get("vrf_id").(int))
+	tenantID := int64(d.Get("tenant_id").(int))
+	description := d.Get("description").(string)
+	dnsName := d.Get("dns_name").(string)
+
+	status, err := ipAddressStatusChoices.parse(d.Get("status").(string))
+	if err != nil {
+		return err
+	}
+
+	if dnsName != "" {
+		if err := meta.(*ProviderNetboxClient).requireFeature(featureDNSName, "dns_name"); err != nil {
+			return err
+		}
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var parm = ipam.NewIPAMIPAddressesUpdateParams().
+		WithID(id).
+		WithContext(withExplicitFields(context.Background(), map[string]interface{}{
+			"vrf":      nil,
+			"tenant":   nil,
+			"dns_name": dnsName,
+		})).
+		WithData(
+			&models.IPAddressCreateUpdate{
+				Address:      &address,
+				Description:  description,
+				Vrf:          vrfID,
+				Tenant:       tenantID,
+				Status:       status,
+				Tags:         expandTags(d, meta),
+				CustomFields: customFields,
+			},
+		)
+
+	log.Debugf("Executing IPAMIPAddressesUpdate against Netbox: %v", parm)
+
+	out, err := netboxClient.IPAM.IPAMIPAddressesUpdate(parm, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMIPAddressesUpdate: %v", err)
+
+		return err
+	}
+
+	log.Debugf("Done Executing IPAMIPAddressesUpdate: %v", out)
+
+	return nil
+}
+
+// resourceNetboxIpamAvailableIPRead reads an allocated IP Address by ID.
+func resourceNetboxIpamAvailableIPRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/ip-address")
+	if err != nil {
+		return err
+	}
+
+	var readResult ipAddressPayload
+
+	err = c.doJSON(http.MethodGet, fmt.Sprintf("/ipam/ip-addresses/%d/", id), nil, &readResult)
+
+	if err != nil {
+		log.Debugf("Error fetching IpAddress ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	var vrfID int64
+	if readResult.Vrf != nil {
+		vrfID = readResult.Vrf.ID
+	}
+
+	var tenantID int64
+	if readResult.Tenant != nil {
+		tenantID = readResult.Tenant.ID
+	}
+
+	if err := d.Set("ip_address_id", readResult.ID); err != nil {
+		return err
+	}
+	if err := d.Set("address", readResult.Address); err != nil {
+		return err
+	}
+	if err := d.Set("vrf_id", vrfID); err != nil {
+		return err
+	}
+	if err := d.Set("tenant_id", tenantID); err != nil {
+		return err
+	}
+
+	if readResult.Status != nil && readResult.Status.Value != nil {
+		if status, ok := ipAddressStatusChoices.slug(*readResult.Status.Value); ok {
+			if err := d.Set("status", status); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := d.Set("dns_name", readResult.DNSName); err != nil {
+		return err
+	}
+	if err := d.Set("description", readResult.Description); err != nil {
+		return err
+	}
+	if err := d.Set("tags", flattenTags(d, meta, readResult.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, readResult.CustomFields)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceNetboxIpamAvailableIPImport imports an IP address by ID, either
+// "42" or "ipam/ip-address/42". The prefix it was allocated from is the most
+// specific prefix of its VRF containing it.
+func resourceNetboxIpamAvailableIPImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(*ProviderNetboxClient)
+
+	id, ok := importNumericID(d.Id(), "ipam/ip-address")
+	if !ok {
+		return nil, fmt.Errorf("invalid IP address ID %q, expected a number", d.Id())
+	}
+
+	var ip ipAddressPayload
+	if err := c.doJSON(http.MethodGet, fmt.Sprintf("/ipam/ip-addresses/%d/", id), nil, &ip); err != nil {
+		return nil, err
+	}
+
+	if ip.Address == nil {
+		return nil, fmt.Errorf("IP address %d has no address", id)
+	}
+
+	prefixID, err := containingPrefix(c, *ip.Address, ip.Vrf, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("prefix_id", prefixID); err != nil {
+		return nil, err
+	}
+
+	return importedState(d, "ipam/ip-address", "ip_address_id", id)
+}
+
+// availableIPBody returns the payload of an allocation request.
+func availableIPBody(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	status, err := ipAddressStatusChoices.parse(d.Get("status").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"status":      status,
+		"description": d.Get("description").(string),
+		"tags":        expandTags(d, meta),
+	}
+
+	if vrfID := d.Get("vrf_id").(int); vrfID != 0 {
+		body["vrf"] = vrfID
+	}
+
+	if tenantID := d.Get("tenant_id").(int); tenantID != 0 {
+		body["tenant"] = tenantID
+	}
+
+	if dnsName := d.Get("dns_name").(string); dnsName != "" {
+		if err := meta.(*ProviderNetboxClient).requireFeature(featureDNSName, "dns_name"); err != nil {
+			return nil, err
+		}
+		body["dns_name"] = dnsName
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	if customFields != nil {
+		body["custom_fields"] = customFields
+	}
+
+	return body, nil
+}
@@ -0,0 +1,191 @@
+package netbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamAvailableIP_create(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"POST /api/ipam/prefixes/42/available-ips/": {status: http.StatusCreated, body: `{"id": 10, "address": "10.0.0.5/24", "vrf": {"id": 3}}`},
+		"PUT /api/ipam/ip-addresses/10/":            {body: `{"id": 10, "address": "10.0.0.5/24"}`},
+		"GET /api/ipam/ip-addresses/10/": {body: `{"id": 10, "address": "10.0.0.5/24", "vrf": {"id": 4}, "tenant": {"id": 7},
+			"status": {"value": 2, "label": "Reserved"}, "description": "web", "tags": ["web"]}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamAvailableIP().Schema, map[string]interface{}{
+		"prefix_id":   42,
+		"vrf_id":      4,
+		"tenant_id":   7,
+		"status":      "reserved",
+		"description": "web",
+		"tags":        []interface{}{"web"},
+	})
+
+	if err := resourceNetboxIpamAvailableIPCreate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	requests := server.received()
+
+	var sent map[string]interface{}
+	if err := json.Unmarshal([]byte(requests[0].body), &sent); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"status":      float64(2),
+		"vrf":         float64(4),
+		"tenant":      float64(7),
+		"description": "web",
+		"tags":        []interface{}{"web"},
+	}
+	if !reflect.DeepEqual(sent, expected) {
+		t.Fatalf("expected %v to be sent, got %v", expected, sent)
+	}
+
+	// The server picked the VRF of the prefix, which is corrected right away.
+	if len(requests) != 2 || requests[1].method != http.MethodPut {
+		t.Fatalf("expected the VRF to be updated, got %v", requests)
+	}
+
+	if d.Id() != "ipam/ip-address/10" || d.Get("address") != "10.0.0.5/24" {
+		t.Fatalf("unexpected ID %q and address %v", d.Id(), d.Get("address"))
+	}
+
+	if err := resourceNetboxIpamAvailableIPRead(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state := map[string]interface{}{
+		"prefix_id":   42,
+		"vrf_id":      4,
+		"tenant_id":   7,
+		"status":      "reserved",
+		"description": "web",
+	}
+	for name, value := range state {
+		if actual := d.Get(name); actual != value {
+			t.Errorf("expected %s to be %v, got %v", name, value, actual)
+		}
+	}
+}
+
+func TestResourceNetboxIpamAvailableIP_concurrent(t *testing.T) {
+	// The fake server is as careless as Netbox: concurrent requests read the
+	// same next free address.
+	var next int32
+	allocate := func(r testRequest) testRoute {
+		n := atomic.LoadInt32(&next) + 1
+		time.Sleep(5 * time.Millisecond)
+		atomic.StoreInt32(&next, n)
+		return testRoute{status: http.StatusCreated, body: fmt.Sprintf(`{"id": %d, "address": "10.0.0.%d/24"}`, n, n)}
+	}
+
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"POST /api/ipam/prefixes/42/available-ips/": {handler: allocate},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	var resources []*schema.ResourceData
+	for i := 0; i < 10; i++ {
+		resources = append(resources, schema.TestResourceDataRaw(t, resourceNetboxIpamAvailableIP().Schema, map[string]interface{}{
+			"prefix_id": 42,
+		}))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(resources))
+	for _, d := range resources {
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+			errs <- resourceNetboxIpamAvailableIPCreate(d, meta)
+		}(d)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, d := range resources {
+		address := d.Get("address").(string)
+		if seen[address] {
+			t.Fatalf("address %s was allocated twice", address)
+		}
+		seen[address] = true
+	}
+}
+
+func TestResourceNetboxIpamAvailableIP_exhausted(t *testing.T) {
+	for _, route := range []testRoute{
+		{status: http.StatusNoContent, body: `{"detail": "An insufficient number of IP addresses are available within the prefix 10.0.0.0/30 (1 requested, 0 available)"}`},
+		{status: http.StatusConflict, body: `{"detail": "An insufficient number of IP addresses are available within the prefix 10.0.0.0/30 (1 requested, 0 available)"}`},
+	} {
+		server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+			"POST /api/ipam/prefixes/42/available-ips/": route,
+		})
+
+		d := schema.TestResourceDataRaw(t, resourceNetboxIpamAvailableIP().Schema, map[string]interface{}{
+			"prefix_id": 42,
+		})
+
+		err := resourceNetboxIpamAvailableIPCreate(d, server.meta(t))
+		if err == nil || !strings.Contains(err.Error(), "prefix 42 has no free IP address left") {
+			t.Errorf("HTTP %d: expected an exhausted prefix error, got %v", route.status, err)
+		}
+		if d.Id() != "" {
+			t.Errorf("HTTP %d: expected no ID, got %q", route.status, d.Id())
+		}
+
+		server.Close()
+	}
+}
+
+func TestResourceNetboxIpamAvailableIP_import(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/ip-addresses/10/": {body: `{"id": 10, "address": "10.0.0.5/24", "vrf": null}`},
+		"GET /api/ipam/prefixes/": {body: `{"count": 2, "next": null, "previous": null, "results": [
+			{"id": 1, "prefix": "10.0.0.0/16"},
+			{"id": 42, "prefix": "10.0.0.0/24"}
+		]}`},
+	})
+	defer server.Close()
+
+	for _, id := range []string{"10", "ipam/ip-address/10"} {
+		d := resourceNetboxIpamAvailableIP().TestResourceData()
+		d.SetId(id)
+
+		imported, err := resourceNetboxIpamAvailableIPImport(d, server.meta(t))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if imported[0].Id() != "ipam/ip-address/10" || imported[0].Get("prefix_id") != 42 || imported[0].Get("ip_address_id") != 10 {
+			t.Fatalf("unexpected import of %q: %s, prefix %v", id, imported[0].Id(), imported[0].Get("prefix_id"))
+		}
+	}
+
+	requests := server.received()
+	if last := requests[len(requests)-1].path; last != "/api/ipam/prefixes/?contains=10.0.0.5&limit=0&vrf_id=null" {
+		t.Fatalf("unexpected prefix lookup %s", last)
+	}
+}
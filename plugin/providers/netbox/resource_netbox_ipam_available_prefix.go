@@ -0,0 +1,310 @@
+package netbox
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceNetboxIpamAvailablePrefix is the core Terraform resource structure for the netbox_ipam_available_prefix resource.
+//
+// The prefix is allocated once from the free space of its parent; the other
+// attributes are then managed like a netbox_ipam_prefix, sharing its Read,
+// Update and Delete.
+func resourceNetboxIpamAvailablePrefix() *schema.Resource {
+	return upgradeResourceID(&schema.Resource{
+		Create: resourceNetboxIpamAvailablePrefixCreate,
+		Read:   resourceNetboxIpamPrefixRead,
+		Update: resourceNetboxIpamPrefixUpdate,
+		Delete: resourceNetboxIpamPrefixDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetboxIpamAvailablePrefixImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"parent_prefix_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"parent_filter"},
+				Description:   "ID of the prefix to allocate from.",
+			},
+			"parent_filter": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"parent_prefix_id"},
+				Description:   "Search terms of the prefix to allocate from, as for the netbox_prefixes data source. They must match exactly one prefix.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"query": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"within": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"family": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: familyChoices.validate,
+						},
+						"tenant": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"site": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"role": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"vlan_vid": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"prefix_length": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 128),
+			},
+			"prefix": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The allocated prefix, in CIDR notation.",
+			},
+			"prefix_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"vrf_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "VRF of the prefix, always the VRF of the parent.",
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"is_pool": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "active",
+				ValidateFunc:     prefixStatusChoices.validate,
+				DiffSuppressFunc: prefixStatusChoices.suppressEquivalent,
+			},
+			"role_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"site_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"vlan_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
+		},
+	}, "ipam/prefix", "prefix_id")
+}
+
+// resourceNetboxIpamAvailablePrefixCreate allocates the first free prefix of the requested length from a parent prefix in Netbox.
+func resourceNetboxIpamAvailablePrefixCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	parentID, err := availablePrefixParent(d, meta)
+	if err != nil {
+		return err
+	}
+
+	body, err := availablePrefixBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// Netbox hands out the same prefix to concurrent requests. Addresses are
+	// allocated under the same key, they come out of the same space.
+	lockKey := allocationLockKey("ipam/prefix", int(parentID))
+	netboxMutexKV.Lock(lockKey)
+	defer netboxMutexKV.Unlock(lockKey)
+
+	log.Debugf("Allocating a /%d prefix from prefix %d: %v", body["prefix_length"], parentID, body)
+
+	var out prefixPayload
+	err = c.doJSON(http.MethodPost, fmt.Sprintf("/ipam/prefixes/%d/available-prefixes/", parentID), body, &out)
+
+	if err != nil {
+		log.Debugf("Failed to allocate a prefix from prefix %d: %v", parentID, err)
+
+		if isNotFound(err) {
+			return fmt.Errorf("prefix %d does not exist", parentID)
+		}
+		if isConflict(err) {
+			return fmt.Errorf("prefix %d has no free /%d prefix left", parentID, body["prefix_length"])
+		}
+		return err
+	}
+
+	// Netbox 2.5 answers 204 No Content when the parent is full.
+	if out.ID == 0 || out.Prefix == nil {
+		return fmt.Errorf("prefix %d has no free /%d prefix left", parentID, body["prefix_length"])
+	}
+
+	d.SetId(fmt.Sprintf("ipam/prefix/%d", out.ID))
+	if err := d.Set("prefix_id", out.ID); err != nil {
+		return err
+	}
+	if err := d.Set("parent_prefix_id", parentID); err != nil {
+		return err
+	}
+
+	log.Debugf("Allocated prefix %s from prefix %d", *out.Prefix, parentID)
+
+	return resourceNetboxIpamPrefixRead(d, meta)
+}
+
+// resourceNetboxIpamAvailablePrefixImport imports a prefix by ID, either
+// "42" or "ipam/prefix/42". Its parent is the most specific larger prefix of
+// its VRF containing it.
+func resourceNetboxIpamAvailablePrefixImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(*ProviderNetboxClient)
+
+	id, ok := importNumericID(d.Id(), "ipam/prefix")
+	if !ok {
+		return nil, fmt.Errorf("invalid prefix ID %q, expected a number", d.Id())
+	}
+
+	readResult, err := c.client.IPAM.IPAMPrefixesRead(ipam.NewIPAMPrefixesReadParams().WithID(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if readResult.Payload.Prefix == nil {
+		return nil, fmt.Errorf("prefix %d has no prefix", id)
+	}
+
+	parentID, err := containingPrefix(c, *readResult.Payload.Prefix, readResult.Payload.Vrf, true)
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi((*readResult.Payload.Prefix)[strings.Index(*readResult.Payload.Prefix, "/")+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Set("parent_prefix_id", parentID); err != nil {
+		return nil, err
+	}
+	if err := d.Set("prefix_length", length); err != nil {
+		return nil, err
+	}
+
+	return importedState(d, "ipam/prefix", "prefix_id", id)
+}
+
+// prefixPayload is the part of an allocated prefix the allocation needs.
+type prefixPayload struct {
+	ID     int64   `json:"id"`
+	Prefix *string `json:"prefix"`
+}
+
+// availablePrefixParent returns the ID of the prefix to allocate from, looking
+// it up when parent_filter is set.
+func availablePrefixParent(d *schema.ResourceData, meta interface{}) (int64, error) {
+	if id, ok := d.GetOk("parent_prefix_id"); ok {
+		return int64(id.(int)), nil
+	}
+
+	filters := d.Get("parent_filter").([]interface{})
+	if len(filters) == 0 {
+		return 0, fmt.Errorf("one of parent_prefix_id or parent_filter must be set")
+	}
+
+	// An empty block has a nil element.
+	raw, _ := filters[0].(map[string]interface{})
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	filter := prefixFilter{}
+	filter.Query, _ = raw["query"].(string)
+	filter.Within, _ = raw["within"].(string)
+	filter.Family, _ = raw["family"].(string)
+	filter.Tenant, _ = raw["tenant"].(string)
+	filter.Site, _ = raw["site"].(string)
+	filter.Role, _ = raw["role"].(string)
+	filter.VlanVid, _ = raw["vlan_vid"].(int)
+
+	parent, err := findPrefix(meta.(*ProviderNetboxClient).client, filter)
+	if err != nil {
+		return 0, fmt.Errorf("parent_filter: %s", err)
+	}
+
+	return parent.ID, nil
+}
+
+// availablePrefixBody returns the payload of an allocation request.
+func availablePrefixBody(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	status, err := prefixStatusChoices.parse(d.Get("status").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"prefix_length": d.Get("prefix_length").(int),
+		"status":        status,
+		"is_pool":       d.Get("is_pool").(bool),
+		"description":   d.Get("description").(string),
+		"tags":          expandTags(d, meta),
+	}
+
+	for attribute, field := range map[string]string{
+		"tenant_id": "tenant",
+		"role_id":   "role",
+		"site_id":   "site",
+		"vlan_id":   "vlan",
+	} {
+		if id := d.Get(attribute).(int); id != 0 {
+			body[field] = id
+		}
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	if customFields != nil {
+		body["custom_fields"] = customFields
+	}
+
+	return body, nil
+}
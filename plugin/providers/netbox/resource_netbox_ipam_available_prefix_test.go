@@ -0,0 +1,253 @@
+package netbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamAvailablePrefix_create(t *testing.T) {
+	cases := []struct {
+		version string
+		status  interface{}
+	}{
+		{"2.5", float64(2)},
+		{"2.6", float64(2)},
+		{"2.7", "reserved"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.version, func(t *testing.T) {
+			server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+				"POST /api/ipam/prefixes/1/available-prefixes/": {status: http.StatusCreated, body: `{"id": 2, "prefix": "192.168.100.0/24"}`},
+				"GET /api/ipam/prefixes/2/":                     {body: testFixture(t, "api", tc.version, "prefix.json")},
+			})
+			defer server.Close()
+
+			meta := server.meta(t)
+
+			d := schema.TestResourceDataRaw(t, resourceNetboxIpamAvailablePrefix().Schema, map[string]interface{}{
+				"parent_prefix_id": 1,
+				"prefix_length":    24,
+				"status":           "reserved",
+				"site_id":          5,
+				"description":      "web",
+			})
+
+			if err := resourceNetboxIpamAvailablePrefixCreate(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			var sent map[string]interface{}
+			if err := json.Unmarshal([]byte(server.received()[0].body), &sent); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			expected := map[string]interface{}{
+				"prefix_length": float64(24),
+				"status":        tc.status,
+				"is_pool":       false,
+				"site":          float64(5),
+				"description":   "web",
+				"tags":          []interface{}{},
+			}
+			if !reflect.DeepEqual(sent, expected) {
+				t.Fatalf("expected %v to be sent, got %v", expected, sent)
+			}
+
+			if d.Id() != "ipam/prefix/2" || d.Get("prefix") != "192.168.100.0/24" || d.Get("parent_prefix_id") != 1 {
+				t.Fatalf("unexpected ID %q, prefix %v and parent %v", d.Id(), d.Get("prefix"), d.Get("parent_prefix_id"))
+			}
+		})
+	}
+}
+
+func TestResourceNetboxIpamAvailablePrefix_parentFilter(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/prefixes/?family=4&limit=2&q=container&site=inkopolis": {body: `{"count": 1, "next": null, "previous": null, "results": [
+			{"id": 1, "prefix": "192.168.0.0/16"}
+		]}`},
+		"POST /api/ipam/prefixes/1/available-prefixes/": {status: http.StatusCreated, body: `{"id": 2, "prefix": "192.168.100.0/28"}`},
+		"GET /api/ipam/prefixes/2/":                     {body: `{"id": 2, "prefix": "192.168.100.0/28"}`},
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamAvailablePrefix().Schema, map[string]interface{}{
+		"parent_filter": []interface{}{map[string]interface{}{
+			"query":  "container",
+			"family": "IPv4",
+			"site":   "Inkopolis",
+		}},
+		"prefix_length": 28,
+	})
+
+	if err := resourceNetboxIpamAvailablePrefixCreate(d, server.meta(t)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Get("parent_prefix_id") != 1 || d.Get("prefix") != "192.168.100.0/28" {
+		t.Fatalf("unexpected parent %v and prefix %v", d.Get("parent_prefix_id"), d.Get("prefix"))
+	}
+}
+
+func TestResourceNetboxIpamAvailablePrefix_familyValidation(t *testing.T) {
+	s := resourceNetboxIpamAvailablePrefix().Schema["parent_filter"].Elem.(*schema.Resource).Schema["family"]
+
+	for _, family := range []string{"4", "6", "ipv4", "IPv6"} {
+		if _, errs := s.ValidateFunc(family, "family"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", family, errs)
+		}
+	}
+
+	for _, family := range []string{"", "0", "5", "inet"} {
+		if _, errs := s.ValidateFunc(family, "family"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", family)
+		}
+	}
+}
+
+func TestResourceNetboxIpamAvailablePrefix_parentRequired(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamAvailablePrefix().Schema, map[string]interface{}{
+		"prefix_length": 28,
+	})
+
+	err := resourceNetboxIpamAvailablePrefixCreate(d, &ProviderNetboxClient{})
+	if err == nil || !strings.Contains(err.Error(), "one of parent_prefix_id or parent_filter must be set") {
+		t.Fatalf("expected a missing parent error, got %v", err)
+	}
+}
+
+func TestResourceNetboxIpamAvailablePrefix_exhausted(t *testing.T) {
+	for _, route := range []testRoute{
+		{status: http.StatusNoContent, body: `{"detail": "Insufficient space is available to accommodate the requested prefix size(s)"}`},
+		{status: http.StatusConflict, body: `{"detail": "Insufficient space is available to accommodate the requested prefix size(s)"}`},
+	} {
+		server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+			"POST /api/ipam/prefixes/1/available-prefixes/": route,
+		})
+
+		d := schema.TestResourceDataRaw(t, resourceNetboxIpamAvailablePrefix().Schema, map[string]interface{}{
+			"parent_prefix_id": 1,
+			"prefix_length":    24,
+		})
+
+		err := resourceNetboxIpamAvailablePrefixCreate(d, server.meta(t))
+		if err == nil || !strings.Contains(err.Error(), "prefix 1 has no free /24 prefix left") {
+			t.Errorf("HTTP %d: expected an exhausted parent error, got %v", route.status, err)
+		}
+		if d.Id() != "" {
+			t.Errorf("HTTP %d: expected no ID, got %q", route.status, d.Id())
+		}
+
+		server.Close()
+	}
+}
+
+func TestResourceNetboxIpamAvailablePrefix_concurrent(t *testing.T) {
+	// The fake server is as careless as Netbox: concurrent requests read the
+	// same next free prefix.
+	var next int32
+	allocate := func(r testRequest) testRoute {
+		n := atomic.LoadInt32(&next) + 1
+		time.Sleep(5 * time.Millisecond)
+		atomic.StoreInt32(&next, n)
+		return testRoute{status: http.StatusCreated, body: fmt.Sprintf(`{"id": %d, "prefix": "10.%d.0.0/24"}`, n+100, n)}
+	}
+	read := func(r testRequest) testRoute {
+		var id int
+		fmt.Sscanf(r.path, "/api/ipam/prefixes/%d/", &id)
+		return testRoute{body: fmt.Sprintf(`{"id": %d, "prefix": "10.%d.0.0/24"}`, id, id-100)}
+	}
+
+	routes := map[string]testRoute{
+		"POST /api/ipam/prefixes/1/available-prefixes/": {handler: allocate},
+	}
+	for i := 101; i <= 110; i++ {
+		routes[fmt.Sprintf("GET /api/ipam/prefixes/%d/", i)] = testRoute{handler: read}
+	}
+
+	server := newTestNetboxServer(t, "2.5", routes)
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	var resources []*schema.ResourceData
+	for i := 0; i < 10; i++ {
+		resources = append(resources, schema.TestResourceDataRaw(t, resourceNetboxIpamAvailablePrefix().Schema, map[string]interface{}{
+			"parent_prefix_id": 1,
+			"prefix_length":    24,
+		}))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(resources))
+	for _, d := range resources {
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+			errs <- resourceNetboxIpamAvailablePrefixCreate(d, meta)
+		}(d)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, d := range resources {
+		prefix := d.Get("prefix").(string)
+		if seen[prefix] {
+			t.Fatalf("prefix %s was allocated twice", prefix)
+		}
+		seen[prefix] = true
+	}
+}
+
+func TestResourceNetboxIpamAvailablePrefix_import(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/prefixes/2/": {body: `{"id": 2, "prefix": "10.0.0.0/24", "vrf": {"id": 3}}`},
+		"GET /api/ipam/prefixes/": {body: `{"count": 3, "next": null, "previous": null, "results": [
+			{"id": 1, "prefix": "10.0.0.0/8"},
+			{"id": 5, "prefix": "10.0.0.0/16"},
+			{"id": 2, "prefix": "10.0.0.0/24"}
+		]}`},
+	})
+	defer server.Close()
+
+	for _, id := range []string{"2", "ipam/prefix/2"} {
+		d := resourceNetboxIpamAvailablePrefix().TestResourceData()
+		d.SetId(id)
+
+		imported, err := resourceNetboxIpamAvailablePrefixImport(d, server.meta(t))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		state := map[string]interface{}{
+			"prefix_id":        2,
+			"parent_prefix_id": 5,
+			"prefix_length":    24,
+		}
+		for name, value := range state {
+			if actual := imported[0].Get(name); actual != value {
+				t.Errorf("import of %q: expected %s to be %v, got %v", id, name, value, actual)
+			}
+		}
+	}
+
+	requests := server.received()
+	if last := requests[len(requests)-1].path; last != "/api/ipam/prefixes/?contains=10.0.0.0&limit=0&vrf_id=3" {
+		t.Fatalf("unexpected parent lookup %s", last)
+	}
+}
@@ -1,24 +1,28 @@
 package netbox
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/tpretz/go-netbox/netbox/client/ipam"
 	"github.com/tpretz/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 // resourceNetboxIpamIpAddress is the core Terraform resource structure for the netbox_ipam_ip_address resource.
 func resourceNetboxIpamIPAddress() *schema.Resource {
-	return &schema.Resource{
+	return upgradeResourceID(&schema.Resource{
 		Create: resourceNetboxIpamIPAddressCreate,
 		Read:   resourceNetboxIpamIPAddressRead,
 		Update: resourceNetboxIpamIPAddressUpdate,
 		Delete: resourceNetboxIpamIPAddressDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNetboxIpamIPAddressImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -27,8 +31,10 @@ func resourceNetboxIpamIPAddress() *schema.Resource {
 				Computed: true,
 			},
 			"address": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validateCIDRAddress,
+				DiffSuppressFunc: suppressEquivalentCIDR,
 			},
 			"vrf_id": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -39,12 +45,14 @@ func resourceNetboxIpamIPAddress() *schema.Resource {
 				Optional: true,
 			},
 			"status": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: ipAddressStatusChoices.validateValue,
 			},
 			"role_id": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: ipAddressRoleChoices.validateValue,
 			},
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
@@ -58,11 +66,37 @@ func resourceNetboxIpamIPAddress() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
-			// TODO interface - looks hard
-			// TODO tags
-			// TODO custom_fields
+			"dns_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"interface_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"interface_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "dcim",
+				ValidateFunc: validation.StringInSlice([]string{"dcim", "virtualization"}, false),
+				Description:  "Whether interface_id is a device (dcim) or virtual machine (virtualization) interface.",
+			},
+			"primary_for_device": &schema.Schema{
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"primary_for_vm"},
+				Description:   "Make this address the primary IPv4 or IPv6 address of the device owning interface_id.",
+			},
+			"primary_for_vm": &schema.Schema{
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"primary_for_device"},
+				Description:   "Make this address the primary IPv4 or IPv6 address of the virtual machine owning interface_id.",
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
 		},
-	}
+	}, "ipam/ip-address", "ip_address_id")
 }
 
 // resourceNetboxIpamIpAddressCreate creates a new IP Address in Netbox.
@@ -77,19 +111,31 @@ func resourceNetboxIpamIPAddressCreate(d *schema.ResourceData, meta interface{})
 	description := d.Get("description").(string)
 	natInsideID := int64(d.Get("nat_inside_ip_address_id").(int))
 	natOutsideID := int64(d.Get("nat_outside_ip_address_id").(int))
+	interfaceID := int64(d.Get("interface_id").(int))
+
+	explicit, err := ipAddressExplicitFields(d, meta)
+	if err != nil {
+		return err
+	}
 
-	var parm = ipam.NewIPAMIPAddressesCreateParams().WithData(
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var parm = ipam.NewIPAMIPAddressesCreateParams().WithContext(explicit).WithData(
 		&models.IPAddressCreateUpdate{
-			Address:     &address,
-			Description: description,
-			Vrf:         vrfID,
-			Tenant:      tenantID,
-			Status:      status,
-			Role:        roleID,
-			NatInside:   natInsideID,
-			NatOutside:  natOutsideID,
-			// TODO Interface
-			Tags: []string{},
+			Address:      &address,
+			Description:  description,
+			Vrf:          vrfID,
+			Tenant:       tenantID,
+			Status:       status,
+			Role:         roleID,
+			NatInside:    natInsideID,
+			NatOutside:   natOutsideID,
+			Interface:    interfaceID,
+			Tags:         expandTags(d, meta),
+			CustomFields: customFields,
 		},
 	)
 
@@ -104,18 +150,23 @@ func resourceNetboxIpamIPAddressCreate(d *schema.ResourceData, meta interface{})
 	}
 
 	d.SetId(fmt.Sprintf("ipam/ip-address/%d", out.Payload.ID))
-	d.Set("ip_address_id", out.Payload.ID)
+	if err := d.Set("ip_address_id", out.Payload.ID); err != nil {
+		return err
+	}
 
 	log.Debugf("Done Executing IPAMIPAddressesCreate: %v", out)
 
-	return nil
+	return resourceNetboxIpamIPAddressUpdatePrimary(d, meta, out.Payload.ID)
 }
 
 // resourceNetboxIpamIpAddressUpdate applies updates to a IP Address by ID when deltas are detected by Terraform.
 func resourceNetboxIpamIPAddressUpdate(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	id := int64(d.Get("ip_address_id").(int))
+	id, err := netboxID(d, "ipam/ip-address")
+	if err != nil {
+		return err
+	}
 
 	address := d.Get("address").(string)
 	vrfID := int64(d.Get("vrf_id").(int))
@@ -125,21 +176,34 @@ func resourceNetboxIpamIPAddressUpdate(d *schema.ResourceData, meta interface{})
 	description := d.Get("description").(string)
 	natInsideID := int64(d.Get("nat_inside_ip_address_id").(int))
 	natOutsideID := int64(d.Get("nat_outside_ip_address_id").(int))
+	interfaceID := int64(d.Get("interface_id").(int))
+
+	explicit, err := ipAddressExplicitFields(d, meta)
+	if err != nil {
+		return err
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
 
 	var parm = ipam.NewIPAMIPAddressesUpdateParams().
 		WithID(id).
+		WithContext(explicit).
 		WithData(
 			&models.IPAddressCreateUpdate{
-				Address:     &address,
-				Description: description,
-				Vrf:         vrfID,
-				Tenant:      tenantID,
-				Status:      status,
-				Role:        roleID,
-				NatInside:   natInsideID,
-				NatOutside:  natOutsideID,
-				// TODO Interface
-				Tags: []string{},
+				Address:      &address,
+				Description:  description,
+				Vrf:          vrfID,
+				Tenant:       tenantID,
+				Status:       status,
+				Role:         roleID,
+				NatInside:    natInsideID,
+				NatOutside:   natOutsideID,
+				Interface:    interfaceID,
+				Tags:         expandTags(d, meta),
+				CustomFields: customFields,
 			},
 		)
 
@@ -155,62 +219,323 @@ func resourceNetboxIpamIPAddressUpdate(d *schema.ResourceData, meta interface{})
 
 	log.Debugf("Done Executing IPAMIPAddressesUpdate: %v", out)
 
-	return nil
+	return resourceNetboxIpamIPAddressUpdatePrimary(d, meta, id)
 }
 
 // resourceNetboxIpamIpAddressRead reads an existing IP Address by ID.
 func resourceNetboxIpamIPAddressRead(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
+	c := meta.(*ProviderNetboxClient)
 
-	id := int64(d.Get("ip_address_id").(int))
+	id, err := netboxID(d, "ipam/ip-address")
+	if err != nil {
+		return err
+	}
 
-	var readParams = ipam.NewIPAMIPAddressesReadParams().WithID(id)
+	// Read through doJSON as the pinned client does not know about dns_name.
+	var readResult struct {
+		Payload ipAddressPayload
+	}
 
-	readResult, err := netboxClient.IPAM.IPAMIPAddressesRead(readParams, nil)
+	err = c.doJSON(http.MethodGet, fmt.Sprintf("/ipam/ip-addresses/%d/", id), nil, &readResult.Payload)
 
 	if err != nil {
 		log.Debugf("Error fetching IpAddress ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("ip_address_id", readResult.Payload.ID); err != nil {
+		return err
+	}
+
+	if err := d.Set("address", readResult.Payload.Address); err != nil {
 		return err
 	}
-	d.Set("address", readResult.Payload.Address)
 
 	var vrfID int64
 	if readResult.Payload.Vrf != nil {
 		vrfID = readResult.Payload.Vrf.ID
 	}
-	d.Set("vrf_id", vrfID)
+	if err := d.Set("vrf_id", vrfID); err != nil {
+		return err
+	}
 
 	var tenantID int64
 	if readResult.Payload.Tenant != nil {
 		tenantID = readResult.Payload.Tenant.ID
 	}
-	d.Set("tenant_id", tenantID)
+	if err := d.Set("tenant_id", tenantID); err != nil {
+		return err
+	}
 
 	var status int64
-	if readResult.Payload.Status != nil {
+	if readResult.Payload.Status != nil && readResult.Payload.Status.Value != nil {
 		status = *readResult.Payload.Status.Value
 	}
-	d.Set("status", status)
+	if err := d.Set("status", status); err != nil {
+		return err
+	}
 
 	var roleID int64
-	if readResult.Payload.Role != nil {
+	if readResult.Payload.Role != nil && readResult.Payload.Role.Value != nil {
 		roleID = *readResult.Payload.Role.Value
 	}
-	d.Set("role_id", roleID)
+	if err := d.Set("role_id", roleID); err != nil {
+		return err
+	}
 
-	d.Set("description", readResult.Payload.Description)
+	if err := d.Set("description", readResult.Payload.Description); err != nil {
+		return err
+	}
 
 	var natInsideID int64
 	if readResult.Payload.NatInside != nil {
 		natInsideID = readResult.Payload.NatInside.ID
 	}
-	d.Set("nat_inside_id", natInsideID)
+	if err := d.Set("nat_inside_ip_address_id", natInsideID); err != nil {
+		return err
+	}
 
 	var natOutsideID int64
 	if readResult.Payload.NatOutside != nil {
 		natOutsideID = readResult.Payload.NatOutside.ID
 	}
-	d.Set("nat_outside_id", natOutsideID)
+	if err := d.Set("nat_outside_ip_address_id", natOutsideID); err != nil {
+		return err
+	}
+
+	if err := d.Set("dns_name", readResult.Payload.DNSName); err != nil {
+		return err
+	}
+
+	var interfaceID int64
+	primaryForDevice, primaryForVM := false, false
+	if iface := readResult.Payload.Interface; iface != nil {
+		interfaceID = iface.ID
+
+		interfaceType := "dcim"
+		if iface.VirtualMachine != nil {
+			interfaceType = "virtualization"
+		}
+		if err := d.Set("interface_type", interfaceType); err != nil {
+			return err
+		}
+
+		primary, err := ipAddressIsPrimary(c, &readResult.Payload)
+		if err != nil {
+			return err
+		}
+		if interfaceType == "virtualization" {
+			primaryForVM = primary
+		} else {
+			primaryForDevice = primary
+		}
+	}
+	if err := d.Set("interface_id", interfaceID); err != nil {
+		return err
+	}
+	if err := d.Set("primary_for_device", primaryForDevice); err != nil {
+		return err
+	}
+	if err := d.Set("primary_for_vm", primaryForVM); err != nil {
+		return err
+	}
+
+	if err := d.Set("tags", flattenTags(d, meta, readResult.Payload.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, readResult.Payload.CustomFields)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ipAddressPayload is an IP address as read from Netbox, with the fields the
+// pinned client does not know about.
+type ipAddressPayload struct {
+	models.IPAddress
+
+	DNSName string `json:"dns_name"`
+}
+
+// ipAddressPrimaryIPs holds the primary addresses of a device or VM.
+type ipAddressPrimaryIPs struct {
+	PrimaryIP4 *models.NestedIPAddress `json:"primary_ip4"`
+	PrimaryIP6 *models.NestedIPAddress `json:"primary_ip6"`
+}
+
+// ipAddressExplicitFields returns the request context sending the fields the
+// pinned client leaves out: cleared relations, the interface type and the
+// DNS name.
+func ipAddressExplicitFields(d *schema.ResourceData, meta interface{}) (context.Context, error) {
+	c := meta.(*ProviderNetboxClient)
+
+	interfaceID := d.Get("interface_id").(int)
+	interfaceType := d.Get("interface_type").(string)
+
+	for attribute, wanted := range map[string]string{"primary_for_device": "dcim", "primary_for_vm": "virtualization"} {
+		if !d.Get(attribute).(bool) {
+			continue
+		}
+		if interfaceID == 0 || interfaceType != wanted {
+			return nil, fmt.Errorf("%s requires interface_id to be a %s interface", attribute, wanted)
+		}
+	}
+
+	dnsName := d.Get("dns_name").(string)
+	if dnsName != "" {
+		if err := c.requireFeature(featureDNSName, "dns_name"); err != nil {
+			return nil, err
+		}
+	}
+
+	var assignedObjectType interface{}
+	if interfaceID != 0 {
+		assignedObjectType = ipAddressAssignedObjectTypes[interfaceType]
+	}
+
+	return withExplicitFields(context.Background(), map[string]interface{}{
+		"vrf":                  nil,
+		"tenant":               nil,
+		"role":                 nil,
+		"nat_inside":           nil,
+		"nat_outside":          nil,
+		"interface":            nil,
+		"assigned_object_type": assignedObjectType,
+		"dns_name":             dnsName,
+	}), nil
+}
+
+// ipAddressAssignedObjectTypes maps interface_type to the object type of the
+// interface since Netbox 2.9.
+var ipAddressAssignedObjectTypes = map[string]string{
+	"dcim":           "dcim.interface",
+	"virtualization": "virtualization.vminterface",
+}
+
+// ipAddressPrimaryField returns the field of a device or VM holding address
+// as its primary IP.
+func ipAddressPrimaryField(address string) (string, error) {
+	ip, _, err := net.ParseCIDR(address)
+	if err != nil {
+		return "", err
+	}
+	if ip.To4() != nil {
+		return "primary_ip4", nil
+	}
+	return "primary_ip6", nil
+}
+
+// ipAddressParentPath returns the API path of the device or VM owning an
+// interface.
+func ipAddressParentPath(c *ProviderNetboxClient, interfaceType string, interfaceID int64) (string, error) {
+	var iface struct {
+		Device         *models.NestedDevice         `json:"device"`
+		VirtualMachine *models.NestedVirtualMachine `json:"virtual_machine"`
+	}
+
+	if interfaceType == "virtualization" {
+		if err := c.doJSON(http.MethodGet, fmt.Sprintf("/virtualization/interfaces/%d/", interfaceID), nil, &iface); err != nil {
+			return "", err
+		}
+		if iface.VirtualMachine != nil {
+			return fmt.Sprintf("/virtualization/virtual-machines/%d/", iface.VirtualMachine.ID), nil
+		}
+	} else {
+		if err := c.doJSON(http.MethodGet, fmt.Sprintf("/dcim/interfaces/%d/", interfaceID), nil, &iface); err != nil {
+			return "", err
+		}
+		if iface.Device != nil {
+			return fmt.Sprintf("/dcim/devices/%d/", iface.Device.ID), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s interface %d has no parent", interfaceType, interfaceID)
+}
+
+// ipAddressIsPrimary reports whether an IP address is the primary address of
+// the device or VM owning its interface.
+func ipAddressIsPrimary(c *ProviderNetboxClient, ip *ipAddressPayload) (bool, error) {
+	var path string
+	switch {
+	case ip.Interface.VirtualMachine != nil:
+		path = fmt.Sprintf("/virtualization/virtual-machines/%d/", ip.Interface.VirtualMachine.ID)
+	case ip.Interface.Device != nil:
+		path = fmt.Sprintf("/dcim/devices/%d/", ip.Interface.Device.ID)
+	default:
+		return false, nil
+	}
+
+	var parent ipAddressPrimaryIPs
+	if err := c.doJSON(http.MethodGet, path, nil, &parent); err != nil {
+		return false, err
+	}
+
+	return (parent.PrimaryIP4 != nil && parent.PrimaryIP4.ID == ip.ID) ||
+		(parent.PrimaryIP6 != nil && parent.PrimaryIP6.ID == ip.ID), nil
+}
+
+// ipAddressPrimaryAttribute returns the attribute making an address primary
+// for the parent of an interface of the given type.
+func ipAddressPrimaryAttribute(interfaceType string) string {
+	if interfaceType == "virtualization" {
+		return "primary_for_vm"
+	}
+	return "primary_for_device"
+}
+
+// resourceNetboxIpamIPAddressUpdatePrimary sets the address as primary IP of
+// the parent of its interface, or clears it from the previous parent when it
+// was primary there.
+func resourceNetboxIpamIPAddressUpdatePrimary(d *schema.ResourceData, meta interface{}, id int64) error {
+	c := meta.(*ProviderNetboxClient)
+
+	field, err := ipAddressPrimaryField(d.Get("address").(string))
+	if err != nil {
+		return err
+	}
+
+	oldType, newType := d.GetChange("interface_type")
+	oldID, newID := d.GetChange("interface_id")
+	oldPrimary, _ := d.GetChange(ipAddressPrimaryAttribute(oldType.(string)))
+	newPrimary := d.Get(ipAddressPrimaryAttribute(newType.(string))).(bool)
+
+	moved := oldID != newID || oldType != newType
+	if oldPrimary.(bool) && oldID.(int) != 0 && (moved || !newPrimary) {
+		path, err := ipAddressParentPath(c, oldType.(string), int64(oldID.(int)))
+		if err != nil {
+			return err
+		}
+
+		var parent ipAddressPrimaryIPs
+		if err := c.doJSON(http.MethodGet, path, nil, &parent); err != nil {
+			return err
+		}
+
+		current := parent.PrimaryIP4
+		if field == "primary_ip6" {
+			current = parent.PrimaryIP6
+		}
+
+		if current != nil && current.ID == id {
+			log.Debugf("Clearing %s of %s", field, path)
+			if err := c.doJSON(http.MethodPatch, path, map[string]interface{}{field: nil}, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if newPrimary {
+		path, err := ipAddressParentPath(c, newType.(string), int64(newID.(int)))
+		if err != nil {
+			return err
+		}
+
+		log.Debugf("Setting %s of %s to IP address %d", field, path, id)
+		if err := c.doJSON(http.MethodPatch, path, map[string]interface{}{field: id}, nil); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -219,7 +544,10 @@ func resourceNetboxIpamIPAddressRead(d *schema.ResourceData, meta interface{}) e
 func resourceNetboxIpamIPAddressDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Debugf("Deleting IpAddress: %v\n", d)
 
-	id := int64(d.Get("ip_address_id").(int))
+	id, err := netboxID(d, "ipam/ip-address")
+	if err != nil {
+		return err
+	}
 
 	var deleteParameters = ipam.NewIPAMIPAddressesDeleteParams().WithID(id)
 
@@ -229,9 +557,27 @@ func resourceNetboxIpamIPAddressDelete(d *schema.ResourceData, meta interface{})
 
 	if err != nil {
 		log.Debugf("Failed to execute IpamIpAddresssDelete: %v", err)
+
+		return handleDeleteNotFound(err)
 	}
 
 	log.Debugf("Done Executing IpamIpAddresssDelete: %v", out)
 
 	return nil
 }
+
+// resourceNetboxIpamIPAddressImport imports an IP address by ID, either "10"
+// or "ipam/ip-address/10", or by "<vrf name>/<address>" such as
+// "Toni Kensa/10.0.0.1/24", the VRF name being left out in the global table.
+func resourceNetboxIpamIPAddressImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/ip-address")
+	if !ok {
+		var err error
+		id, err = ipAddressIDByKey(meta.(*ProviderNetboxClient).client, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importedState(d, "ipam/ip-address", "ip_address_id", id)
+}
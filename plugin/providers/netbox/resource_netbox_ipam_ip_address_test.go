@@ -0,0 +1,199 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamIPAddress_interface(t *testing.T) {
+	cases := []struct {
+		version  string
+		read     string
+		expected map[string]interface{}
+	}{
+		{
+			version: "2.6",
+			read: `{"id": 10, "address": "192.168.100.1/24", "dns_name": "router.example.com",
+				"interface": {"id": 7, "name": "eth0", "device": {"id": 3, "name": "router"}, "virtual_machine": null}}`,
+			expected: map[string]interface{}{"interface": float64(7), "dns_name": "router.example.com"},
+		},
+		{
+			version: "2.9",
+			read: `{"id": 10, "address": "192.168.100.1/24", "dns_name": "router.example.com",
+				"assigned_object_type": "dcim.interface", "assigned_object_id": 7,
+				"assigned_object": {"id": 7, "name": "eth0", "device": {"id": 3, "name": "router"}}}`,
+			expected: map[string]interface{}{"assigned_object_type": "dcim.interface", "assigned_object_id": float64(7), "dns_name": "router.example.com"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.version, func(t *testing.T) {
+			server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+				"POST /api/ipam/ip-addresses/":   {status: http.StatusCreated, body: `{"id": 10, "address": "192.168.100.1/24"}`},
+				"GET /api/ipam/ip-addresses/10/": {body: tc.read},
+				"GET /api/dcim/interfaces/7/":    {body: `{"id": 7, "name": "eth0", "device": {"id": 3, "name": "router"}}`},
+				"PATCH /api/dcim/devices/3/":     {body: `{"id": 3}`},
+				"GET /api/dcim/devices/3/":       {body: `{"id": 3, "primary_ip4": {"id": 10, "address": "192.168.100.1/24"}, "primary_ip6": null}`},
+			})
+			defer server.Close()
+
+			meta := server.meta(t)
+
+			d := schema.TestResourceDataRaw(t, resourceNetboxIpamIPAddress().Schema, map[string]interface{}{
+				"address":            "192.168.100.1/24",
+				"dns_name":           "router.example.com",
+				"interface_id":       7,
+				"primary_for_device": true,
+			})
+
+			if err := resourceNetboxIpamIPAddressCreate(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			requests := server.received()
+
+			var sent map[string]interface{}
+			if err := json.Unmarshal([]byte(requests[0].body), &sent); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			for name, value := range tc.expected {
+				if !reflect.DeepEqual(sent[name], value) {
+					t.Errorf("expected %s to be sent as %#v, got %#v", name, value, sent[name])
+				}
+			}
+			if _, ok := sent["interface"]; ok && tc.version == "2.9" {
+				t.Errorf("expected interface to be replaced by assigned_object_id, got %v", sent)
+			}
+
+			last := requests[len(requests)-1]
+			if last.method != http.MethodPatch || last.path != "/api/dcim/devices/3/" || last.body != `{"primary_ip4":10}` {
+				t.Fatalf("expected the device primary IPv4 to be set, got %s %s %s", last.method, last.path, last.body)
+			}
+
+			if err := resourceNetboxIpamIPAddressRead(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			state := map[string]interface{}{
+				"dns_name":           "router.example.com",
+				"interface_id":       7,
+				"interface_type":     "dcim",
+				"primary_for_device": true,
+				"primary_for_vm":     false,
+			}
+			for name, value := range state {
+				if actual := d.Get(name); actual != value {
+					t.Errorf("expected %s to be %v, got %v", name, value, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceNetboxIpamIPAddress_reassigned(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/ip-addresses/10/": {body: `{"id": 10, "address": "2001:db8::1/64",
+			"interface": {"id": 8, "name": "eth1", "device": null, "virtual_machine": {"id": 4, "name": "vm"}}}`},
+		"GET /api/virtualization/virtual-machines/4/": {body: `{"id": 4, "primary_ip4": null, "primary_ip6": {"id": 10}}`},
+	})
+	defer server.Close()
+
+	d := resourceNetboxIpamIPAddress().TestResourceData()
+	d.SetId("ipam/ip-address/10")
+	d.Set("ip_address_id", 10)
+	d.Set("interface_id", 7)
+	d.Set("interface_type", "dcim")
+	d.Set("primary_for_device", true)
+
+	if err := resourceNetboxIpamIPAddressRead(d, server.meta(t)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state := map[string]interface{}{
+		"interface_id":       8,
+		"interface_type":     "virtualization",
+		"primary_for_device": false,
+		"primary_for_vm":     true,
+	}
+	for name, value := range state {
+		if actual := d.Get(name); actual != value {
+			t.Errorf("expected %s to be %v, got %v", name, value, actual)
+		}
+	}
+}
+
+func TestResourceNetboxIpamIPAddress_invalid(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{})
+	defer server.Close()
+
+	cases := []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{"address": "10.0.0.1/24", "dns_name": "host.example.com"},
+			"dns_name requires Netbox API >= 2.6",
+		},
+		{
+			map[string]interface{}{"address": "10.0.0.1/24", "interface_id": 7, "primary_for_vm": true},
+			"primary_for_vm requires interface_id to be a virtualization interface",
+		},
+		{
+			map[string]interface{}{"address": "10.0.0.1/24", "primary_for_device": true},
+			"primary_for_device requires interface_id to be a dcim interface",
+		},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceNetboxIpamIPAddress().Schema, tc.config)
+
+		err := resourceNetboxIpamIPAddressCreate(d, server.meta(t))
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("expected an error containing %q, got %v", tc.expected, err)
+		}
+	}
+
+	if requests := server.received(); len(requests) != 0 {
+		t.Fatalf("expected no request to be sent, got %v", requests)
+	}
+}
+
+func TestResourceNetboxIpamIPAddress_validation(t *testing.T) {
+	r := resourceNetboxIpamIPAddress()
+
+	cases := []struct {
+		key     string
+		valid   []interface{}
+		invalid []interface{}
+	}{
+		{"address", []interface{}{"10.0.0.1/24", "10.0.0.0/24", "2001:DB8::1/64"}, []interface{}{"", "10.0.0.1", "2001:db8::1", "10.0.0.1/33"}},
+		{"status", []interface{}{1, 2, 3, 5}, []interface{}{0, 4, 10}},
+		{"role_id", []interface{}{10, 20, 44}, []interface{}{0, 1, 45}},
+	}
+
+	for _, tc := range cases {
+		s := r.Schema[tc.key]
+		for _, value := range tc.valid {
+			if _, errs := s.ValidateFunc(value, tc.key); len(errs) > 0 {
+				t.Errorf("%s: expected %v to be valid, got %v", tc.key, value, errs)
+			}
+		}
+		for _, value := range tc.invalid {
+			if _, errs := s.ValidateFunc(value, tc.key); len(errs) == 0 {
+				t.Errorf("%s: expected %v to be invalid", tc.key, value)
+			}
+		}
+	}
+
+	if !r.Schema["address"].DiffSuppressFunc("address", "2001:db8::1/64", "2001:DB8:0::1/64", nil) {
+		t.Errorf("expected 2001:db8::1/64 and 2001:DB8:0::1/64 to be equivalent")
+	}
+	if r.Schema["address"].DiffSuppressFunc("address", "10.0.0.1/24", "10.0.0.1/25", nil) {
+		t.Errorf("expected 10.0.0.1/24 and 10.0.0.1/25 to differ")
+	}
+}
@@ -1,6 +1,7 @@
 package netbox
 
 import (
+	"context"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -12,19 +13,30 @@ import (
 
 // resourceNetboxIpamPrefix is the core Terraform resource structure for the netbox_ipam_Prefix_domain resource.
 func resourceNetboxIpamPrefix() *schema.Resource {
-	return &schema.Resource{
+	return upgradeResourceID(&schema.Resource{
 		Create: resourceNetboxIpamPrefixCreate,
 		Read:   resourceNetboxIpamPrefixRead,
 		Update: resourceNetboxIpamPrefixUpdate,
 		Delete: resourceNetboxIpamPrefixDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNetboxIpamPrefixImport,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceNetboxIpamPrefixV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceNetboxIpamPrefixStateUpgradeV0,
+			},
 		},
 
 		Schema: map[string]*schema.Schema{
 			"prefix": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validateCIDRPrefix,
+				DiffSuppressFunc: suppressEquivalentCIDR,
 			},
 			"prefix_id": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -48,12 +60,28 @@ func resourceNetboxIpamPrefix() *schema.Resource {
 				Optional: true,
 			},
 			"status": &schema.Schema{
-				Type:     schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "active",
+				ValidateFunc:     prefixStatusChoices.validate,
+				DiffSuppressFunc: prefixStatusChoices.suppressEquivalent,
+			},
+			"role_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"site_id": &schema.Schema{
+				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  "0",
 			},
+			"vlan_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
 		},
-	}
+	}, "ipam/prefix", "prefix_id")
 }
 
 // resourceNetboxIpamPrefixCreate creates a new Prefix in Netbox.
@@ -64,17 +92,34 @@ func resourceNetboxIpamPrefixCreate(d *schema.ResourceData, meta interface{}) er
 	description := d.Get("description").(string)
 	vrfID := int64(d.Get("vrf_id").(int))
 	isPool := d.Get("is_pool").(bool)
-	//status := d.Get("status").(string)
 	tenantID := int64(d.Get("tenant_id").(int))
+	roleID := int64(d.Get("role_id").(int))
+	siteID := int64(d.Get("site_id").(int))
+	vlanID := int64(d.Get("vlan_id").(int))
+
+	status, err := prefixStatusChoices.parse(d.Get("status").(string))
+	if err != nil {
+		return err
+	}
 
-	var parm = ipam.NewIPAMPrefixesCreateParams().WithData(
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var parm = ipam.NewIPAMPrefixesCreateParams().WithContext(prefixExplicitFields(status)).WithData(
 		&models.PrefixCreateUpdate{
-			Prefix:      &prefix,
-			Description: description,
-			IsPool:      isPool,
-			Tags:        []string{},
-			Vrf:         vrfID,
-			Tenant:      tenantID,
+			Prefix:       &prefix,
+			Description:  description,
+			IsPool:       isPool,
+			Tags:         expandTags(d, meta),
+			CustomFields: customFields,
+			Vrf:          vrfID,
+			Tenant:       tenantID,
+			Role:         roleID,
+			Site:         siteID,
+			Vlan:         vlanID,
+			Status:       status,
 		},
 	)
 
@@ -90,7 +135,9 @@ func resourceNetboxIpamPrefixCreate(d *schema.ResourceData, meta interface{}) er
 
 	// TODO Probably a better way to parse this ID
 	d.SetId(fmt.Sprintf("ipam/prefix/%d", out.Payload.ID))
-	d.Set("prefix_id", out.Payload.ID)
+	if err := d.Set("prefix_id", out.Payload.ID); err != nil {
+		return err
+	}
 
 	log.Debugf("Done Executing IPAMPrefixesCreate: %v", out)
 
@@ -101,25 +148,46 @@ func resourceNetboxIpamPrefixCreate(d *schema.ResourceData, meta interface{}) er
 func resourceNetboxIpamPrefixUpdate(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	id := int64(d.Get("prefix_id").(int))
+	id, err := netboxID(d, "ipam/prefix")
+	if err != nil {
+		return err
+	}
 
 	prefix := d.Get("prefix").(string)
 	description := d.Get("description").(string)
 	vrfID := int64(d.Get("vrf_id").(int))
 	isPool := d.Get("is_pool").(bool)
-	//status := d.Get("status").(string)
 	tenantID := int64(d.Get("tenant_id").(int))
+	roleID := int64(d.Get("role_id").(int))
+	siteID := int64(d.Get("site_id").(int))
+	vlanID := int64(d.Get("vlan_id").(int))
+
+	status, err := prefixStatusChoices.parse(d.Get("status").(string))
+	if err != nil {
+		return err
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
 
 	var parm = ipam.NewIPAMPrefixesUpdateParams().
 		WithID(id).
+		WithContext(prefixExplicitFields(status)).
 		WithData(
 			&models.PrefixCreateUpdate{
-				Prefix:      &prefix,
-				Description: description,
-				IsPool:      isPool,
-				Tags:        []string{},
-				Vrf:         vrfID,
-				Tenant:      tenantID,
+				Prefix:       &prefix,
+				Description:  description,
+				IsPool:       isPool,
+				Tags:         expandTags(d, meta),
+				CustomFields: customFields,
+				Vrf:          vrfID,
+				Tenant:       tenantID,
+				Role:         roleID,
+				Site:         siteID,
+				Vlan:         vlanID,
+				Status:       status,
 			},
 		)
 
@@ -142,7 +210,10 @@ func resourceNetboxIpamPrefixUpdate(d *schema.ResourceData, meta interface{}) er
 func resourceNetboxIpamPrefixRead(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	id := int64(d.Get("prefix_id").(int))
+	id, err := netboxID(d, "ipam/prefix")
+	if err != nil {
+		return err
+	}
 
 	var readParams = ipam.NewIPAMPrefixesReadParams().WithID(id)
 
@@ -150,6 +221,10 @@ func resourceNetboxIpamPrefixRead(d *schema.ResourceData, meta interface{}) erro
 
 	if err != nil {
 		log.Debugf("Error fetching Prefix ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("prefix_id", readResult.Payload.ID); err != nil {
 		return err
 	}
 
@@ -158,25 +233,89 @@ func resourceNetboxIpamPrefixRead(d *schema.ResourceData, meta interface{}) erro
 		vrfID = readResult.Payload.Vrf.ID
 	}
 
-	d.Set("prefix", readResult.Payload.Prefix)
-	d.Set("description", readResult.Payload.Description)
-	d.Set("vrf_id", vrfID)
-	d.Set("is_pool", readResult.Payload.IsPool)
+	if err := d.Set("prefix", readResult.Payload.Prefix); err != nil {
+		return err
+	}
+	if err := d.Set("description", readResult.Payload.Description); err != nil {
+		return err
+	}
+	if err := d.Set("vrf_id", vrfID); err != nil {
+		return err
+	}
+	if err := d.Set("is_pool", readResult.Payload.IsPool); err != nil {
+		return err
+	}
 
 	var tenantID int64
 	if readResult.Payload.Tenant != nil {
 		tenantID = readResult.Payload.Tenant.ID
 	}
-	d.Set("tenant_id", tenantID)
+	if err := d.Set("tenant_id", tenantID); err != nil {
+		return err
+	}
+
+	var roleID int64
+	if readResult.Payload.Role != nil {
+		roleID = readResult.Payload.Role.ID
+	}
+	if err := d.Set("role_id", roleID); err != nil {
+		return err
+	}
+
+	var siteID int64
+	if readResult.Payload.Site != nil {
+		siteID = readResult.Payload.Site.ID
+	}
+	if err := d.Set("site_id", siteID); err != nil {
+		return err
+	}
+
+	var vlanID int64
+	if readResult.Payload.Vlan != nil {
+		vlanID = readResult.Payload.Vlan.ID
+	}
+	if err := d.Set("vlan_id", vlanID); err != nil {
+		return err
+	}
+
+	if readResult.Payload.Status != nil && readResult.Payload.Status.Value != nil {
+		if status, ok := prefixStatusChoices.slug(*readResult.Payload.Status.Value); ok {
+			if err := d.Set("status", status); err != nil {
+				return err
+			}
+		}
+	}
+	if err := d.Set("tags", flattenTags(d, meta, readResult.Payload.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, readResult.Payload.CustomFields)); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// prefixExplicitFields returns the request context sending the status and
+// clearing the optional relations the pinned client would leave out.
+func prefixExplicitFields(status int64) context.Context {
+	return withExplicitFields(context.Background(), map[string]interface{}{
+		"status": status,
+		"vrf":    nil,
+		"tenant": nil,
+		"role":   nil,
+		"site":   nil,
+		"vlan":   nil,
+	})
+}
+
 // resourceNetboxIpamPrefixDelete deletes an existing Prefix by ID.
 func resourceNetboxIpamPrefixDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Debugf("Deleting Prefix: %v\n", d)
 
-	id := int64(d.Get("prefix_id").(int))
+	id, err := netboxID(d, "ipam/prefix")
+	if err != nil {
+		return err
+	}
 
 	var deleteParameters = ipam.NewIPAMPrefixesDeleteParams().WithID(id)
 
@@ -186,9 +325,27 @@ func resourceNetboxIpamPrefixDelete(d *schema.ResourceData, meta interface{}) er
 
 	if err != nil {
 		log.Debugf("Failed to execute IPAMPrefixesDelete: %v", err)
+
+		return handleDeleteNotFound(err)
 	}
 
 	log.Debugf("Done Executing IPAMPrefixesDelete: %v", out)
 
 	return nil
 }
+
+// resourceNetboxIpamPrefixImport imports a prefix by ID, either "42" or
+// "ipam/prefix/42", or by "<vrf name>/<prefix>" such as
+// "Toni Kensa/10.0.0.0/24", the VRF name being left out in the global table.
+func resourceNetboxIpamPrefixImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/prefix")
+	if !ok {
+		var err error
+		id, err = prefixIDByKey(meta.(*ProviderNetboxClient).client, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importedState(d, "ipam/prefix", "prefix_id", id)
+}
@@ -0,0 +1,59 @@
+package netbox
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceNetboxIpamPrefixV0 is the schema of netbox_ipam_prefix before
+// status was sent to Netbox.
+func resourceNetboxIpamPrefixV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"prefix": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"prefix_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"vrf_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"is_pool": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "0",
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
+		},
+	}
+}
+
+// resourceNetboxIpamPrefixStateUpgradeV0 replaces the "0" status default,
+// which was never sent, by the status Netbox gave those prefixes: active.
+func resourceNetboxIpamPrefixStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if status, ok := rawState["status"].(string); !ok || status == "" || status == "0" {
+		log.Debugf("Upgrading prefix status %q to active", rawState["status"])
+		rawState["status"] = "active"
+	}
+
+	return rawState, nil
+}
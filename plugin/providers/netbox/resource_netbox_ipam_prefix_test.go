@@ -0,0 +1,186 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestResourceNetboxIpamPrefix_statusAndRelations(t *testing.T) {
+	cases := []struct {
+		version string
+		status  interface{}
+	}{
+		{"2.5", float64(0)},
+		{"2.6", float64(0)},
+		{"2.7", "container"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.version, func(t *testing.T) {
+			server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+				"POST /api/ipam/prefixes/":  {status: http.StatusCreated, body: `{"id": 2}`},
+				"GET /api/ipam/prefixes/2/": {body: testFixture(t, "api", tc.version, "prefix.json")},
+			})
+			defer server.Close()
+
+			meta := server.meta(t)
+
+			d := schema.TestResourceDataRaw(t, resourceNetboxIpamPrefix().Schema, map[string]interface{}{
+				"prefix":  "192.168.100.0/24",
+				"status":  "container",
+				"role_id": 4,
+				"site_id": 5,
+			})
+
+			if err := resourceNetboxIpamPrefixCreate(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			var sent map[string]interface{}
+			if err := json.Unmarshal([]byte(server.received()[0].body), &sent); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			expected := map[string]interface{}{
+				"status": tc.status,
+				"role":   float64(4),
+				"site":   float64(5),
+				"vlan":   nil,
+				"vrf":    nil,
+				"tenant": nil,
+			}
+			for name, value := range expected {
+				if actual, ok := sent[name]; !ok || !reflect.DeepEqual(actual, value) {
+					t.Errorf("expected %s to be sent as %#v, got %#v", name, value, actual)
+				}
+			}
+
+			if err := resourceNetboxIpamPrefixRead(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if status := d.Get("status"); status != "container" {
+				t.Fatalf("expected status container, got %v", status)
+			}
+			for _, name := range []string{"role_id", "site_id", "vlan_id"} {
+				if id := d.Get(name); id != 0 {
+					t.Fatalf("expected %s to be cleared, got %v", name, id)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceNetboxIpamPrefix_statusValidation(t *testing.T) {
+	s := resourceNetboxIpamPrefix().Schema["status"]
+
+	for _, status := range []string{"active", "Reserved", "0", "3"} {
+		if _, errs := s.ValidateFunc(status, "status"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", status, errs)
+		}
+	}
+
+	for _, status := range []string{"", "4", "dhcp", "offline"} {
+		if _, errs := s.ValidateFunc(status, "status"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", status)
+		}
+	}
+
+	if !s.DiffSuppressFunc("status", "active", "1", nil) {
+		t.Errorf("expected active and 1 to be equivalent")
+	}
+	if s.DiffSuppressFunc("status", "active", "0", nil) {
+		t.Errorf("expected active and 0 to differ")
+	}
+}
+
+func TestResourceNetboxIpamPrefix_prefixValidation(t *testing.T) {
+	s := resourceNetboxIpamPrefix().Schema["prefix"]
+
+	for _, prefix := range []string{"10.0.0.0/24", "10.0.0.0/8", "2001:db8::/32", "2001:DB8::/32", "192.0.2.1/32"} {
+		if _, errs := s.ValidateFunc(prefix, "prefix"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", prefix, errs)
+		}
+	}
+
+	for _, prefix := range []string{"", "10.0.0.0", "10.0.0.1/24", "10.0.0.0/33", "2001:db8::1/32", "example"} {
+		if _, errs := s.ValidateFunc(prefix, "prefix"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", prefix)
+		}
+	}
+}
+
+func TestResourceNetboxIpamPrefix_ipv6Diff(t *testing.T) {
+	r := resourceNetboxIpamPrefix()
+
+	state := &terraform.InstanceState{
+		ID: "ipam/prefix/1",
+		Attributes: map[string]string{
+			"id":        "ipam/prefix/1",
+			"prefix_id": "1",
+			"prefix":    "2001:db8::/32",
+			"status":    "active",
+			"is_pool":   "false",
+		},
+	}
+
+	cases := map[string]bool{
+		"2001:db8::/32":       false,
+		"2001:DB8::/32":       false,
+		"2001:0db8:0000::/32": false,
+		"2001:db8:1::/48":     true,
+		"2001:db8::/33":       true,
+	}
+
+	for prefix, changed := range cases {
+		raw, err := config.NewRawConfig(map[string]interface{}{"prefix": prefix})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		diff, err := r.Diff(state, terraform.NewResourceConfig(raw), nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if actual := diff != nil && !diff.Empty(); actual != changed {
+			t.Errorf("%s: expected a diff to be %v, got %v", prefix, changed, diff)
+		}
+	}
+}
+
+func TestResourceNetboxIpamPrefixStateUpgradeV0(t *testing.T) {
+	cases := []struct {
+		status   interface{}
+		expected string
+	}{
+		{"0", "active"},
+		{nil, "active"},
+		{"2", "2"},
+		{"reserved", "reserved"},
+	}
+
+	for _, tc := range cases {
+		rawState := map[string]interface{}{
+			"prefix":    "10.0.0.0/24",
+			"prefix_id": float64(42),
+		}
+		if tc.status != nil {
+			rawState["status"] = tc.status
+		}
+
+		actual, err := resourceNetboxIpamPrefixStateUpgradeV0(rawState, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if actual["status"] != tc.expected {
+			t.Errorf("expected status %v to upgrade to %q, got %v", tc.status, tc.expected, actual["status"])
+		}
+		if actual["prefix_id"] != float64(42) {
+			t.Errorf("expected prefix_id to be kept, got %v", actual["prefix_id"])
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package netbox
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceNetboxIpamRole is the core Terraform resource structure for the netbox_ipam_role resource.
+func resourceNetboxIpamRole() *schema.Resource {
+	return upgradeResourceID(&schema.Resource{
+		Create: resourceNetboxIpamRoleCreate,
+		Read:   resourceNetboxIpamRoleRead,
+		Update: resourceNetboxIpamRoleUpdate,
+		Delete: resourceNetboxIpamRoleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetboxIpamRoleImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"role_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"weight": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validation.IntBetween(0, 32767),
+			},
+		},
+	}, "ipam/role", "role_id")
+}
+
+// resourceNetboxIpamRoleCreate creates a new prefix and VLAN role in Netbox.
+func resourceNetboxIpamRoleCreate(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	name := d.Get("name").(string)
+	slug := d.Get("slug").(string)
+	weight := int64(d.Get("weight").(int))
+
+	var parm = ipam.NewIPAMRolesCreateParams().WithData(
+		&models.Role{
+			Name:   &name,
+			Slug:   &slug,
+			Weight: &weight,
+		},
+	)
+
+	log.Debugf("Executing IPAMRolesCreate against Netbox: %v", parm)
+
+	out, err := netboxClient.IPAM.IPAMRolesCreate(parm, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMRolesCreate: %v", err)
+
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("ipam/role/%d", out.Payload.ID))
+	if err := d.Set("role_id", out.Payload.ID); err != nil {
+		return err
+	}
+
+	log.Debugf("Done Executing IPAMRolesCreate: %v", out)
+
+	return nil
+}
+
+// resourceNetboxIpamRoleUpdate applies updates to a role by ID when deltas are detected by Terraform.
+func resourceNetboxIpamRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	id, err := netboxID(d, "ipam/role")
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	slug := d.Get("slug").(string)
+	weight := int64(d.Get("weight").(int))
+
+	var parm = ipam.NewIPAMRolesUpdateParams().
+		WithID(id).
+		WithData(
+			&models.Role{
+				Name:   &name,
+				Slug:   &slug,
+				Weight: &weight,
+			},
+		)
+
+	log.Debugf("Executing IPAMRolesUpdate against Netbox: %v", parm)
+
+	out, err := netboxClient.IPAM.IPAMRolesUpdate(parm, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMRolesUpdate: %v", err)
+
+		return err
+	}
+
+	log.Debugf("Done Executing IPAMRolesUpdate: %v", out)
+
+	return nil
+}
+
+// resourceNetboxIpamRoleRead reads an existing role by ID.
+func resourceNetboxIpamRoleRead(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	id, err := netboxID(d, "ipam/role")
+	if err != nil {
+		return err
+	}
+
+	var readParams = ipam.NewIPAMRolesReadParams().WithID(id)
+
+	readResult, err := netboxClient.IPAM.IPAMRolesRead(readParams, nil)
+
+	if err != nil {
+		log.Debugf("Error fetching role ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	return setIpamRole(d, readResult.Payload)
+}
+
+// resourceNetboxIpamRoleDelete deletes an existing role by ID.
+func resourceNetboxIpamRoleDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Debugf("Deleting role: %v\n", d)
+
+	id, err := netboxID(d, "ipam/role")
+	if err != nil {
+		return err
+	}
+
+	var deleteParameters = ipam.NewIPAMRolesDeleteParams().WithID(id)
+
+	c := meta.(*ProviderNetboxClient).client
+
+	out, err := c.IPAM.IPAMRolesDelete(deleteParameters, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMRolesDelete: %v", err)
+
+		return handleDeleteNotFound(err)
+	}
+
+	log.Debugf("Done Executing IPAMRolesDelete: %v", out)
+
+	return nil
+}
+
+// resourceNetboxIpamRoleImport imports a role by ID, either "4" or
+// "ipam/role/4".
+func resourceNetboxIpamRoleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/role")
+	if !ok {
+		return nil, fmt.Errorf("invalid role ID %q, expected a number", d.Id())
+	}
+
+	return importedState(d, "ipam/role", "role_id", id)
+}
+
+// setIpamRole sets the attributes shared by the role resource and data
+// source.
+func setIpamRole(d *schema.ResourceData, role *models.Role) error {
+	if err := d.Set("role_id", role.ID); err != nil {
+		return err
+	}
+	if err := d.Set("name", role.Name); err != nil {
+		return err
+	}
+	if err := d.Set("slug", role.Slug); err != nil {
+		return err
+	}
+	if err := d.Set("weight", role.Weight); err != nil {
+		return err
+	}
+
+	return nil
+}
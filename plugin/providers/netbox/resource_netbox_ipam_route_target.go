@@ -0,0 +1,241 @@
+package netbox
+
+import (
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceNetboxIpamRouteTarget is the core Terraform resource structure for the netbox_ipam_route_target resource.
+//
+// Route targets appeared in Netbox 2.10, after the API spoken by the pinned
+// client, so they are managed through doJSON.
+func resourceNetboxIpamRouteTarget() *schema.Resource {
+	return upgradeResourceID(&schema.Resource{
+		Create: resourceNetboxIpamRouteTargetCreate,
+		Read:   resourceNetboxIpamRouteTargetRead,
+		Update: resourceNetboxIpamRouteTargetUpdate,
+		Delete: resourceNetboxIpamRouteTargetDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetboxIpamRouteTargetImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"route_target_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The route target value, such as 65000:100.",
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
+		},
+	}, "ipam/route-target", "route_target_id")
+}
+
+// routeTargetPayload is a route target as returned by Netbox.
+type routeTargetPayload struct {
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Tenant       *nestedRouteOwner `json:"tenant"`
+	Description  string            `json:"description"`
+	Tags         []string          `json:"tags"`
+	CustomFields interface{}       `json:"custom_fields"`
+}
+
+// nestedRouteTarget is a route target nested in a VRF.
+type nestedRouteTarget struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// nestedRouteOwner is the tenant nested in a route target.
+type nestedRouteOwner struct {
+	ID int64 `json:"id"`
+}
+
+// resourceNetboxIpamRouteTargetCreate creates a new route target in Netbox.
+func resourceNetboxIpamRouteTargetCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	if err := c.requireFeature(featureRouteTargets, "netbox_ipam_route_target"); err != nil {
+		return err
+	}
+
+	body, err := routeTargetBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Creating route target in Netbox: %v", body)
+
+	var out routeTargetPayload
+	if err := c.doJSON(http.MethodPost, "/ipam/route-targets/", body, &out); err != nil {
+		log.Debugf("Failed to create route target: %v", err)
+
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("ipam/route-target/%d", out.ID))
+	if err := d.Set("route_target_id", out.ID); err != nil {
+		return err
+	}
+
+	log.Debugf("Done creating route target %d", out.ID)
+
+	return nil
+}
+
+// resourceNetboxIpamRouteTargetUpdate applies updates to a route target by ID when deltas are detected by Terraform.
+func resourceNetboxIpamRouteTargetUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/route-target")
+	if err != nil {
+		return err
+	}
+
+	body, err := routeTargetBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Updating route target %d in Netbox: %v", id, body)
+
+	if err := c.doJSON(http.MethodPut, fmt.Sprintf("/ipam/route-targets/%d/", id), body, nil); err != nil {
+		log.Debugf("Failed to update route target %d: %v", id, err)
+
+		return err
+	}
+
+	log.Debugf("Done updating route target %d", id)
+
+	return nil
+}
+
+// resourceNetboxIpamRouteTargetRead reads an existing route target by ID.
+func resourceNetboxIpamRouteTargetRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/route-target")
+	if err != nil {
+		return err
+	}
+
+	var routeTarget routeTargetPayload
+
+	err = c.doJSON(http.MethodGet, fmt.Sprintf("/ipam/route-targets/%d/", id), nil, &routeTarget)
+
+	if err != nil {
+		log.Debugf("Error fetching route target ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("route_target_id", routeTarget.ID); err != nil {
+		return err
+	}
+	if err := d.Set("name", routeTarget.Name); err != nil {
+		return err
+	}
+
+	var tenantID int64
+	if routeTarget.Tenant != nil {
+		tenantID = routeTarget.Tenant.ID
+	}
+	if err := d.Set("tenant_id", tenantID); err != nil {
+		return err
+	}
+
+	if err := d.Set("description", routeTarget.Description); err != nil {
+		return err
+	}
+	if err := d.Set("tags", flattenTags(d, meta, routeTarget.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, routeTarget.CustomFields)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceNetboxIpamRouteTargetDelete deletes an existing route target by ID.
+func resourceNetboxIpamRouteTargetDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Debugf("Deleting route target: %v\n", d)
+
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/route-target")
+	if err != nil {
+		return err
+	}
+
+	if err := c.doJSON(http.MethodDelete, fmt.Sprintf("/ipam/route-targets/%d/", id), nil, nil); err != nil {
+		log.Debugf("Failed to delete route target %d: %v", id, err)
+
+		return handleDeleteNotFound(err)
+	}
+
+	log.Debugf("Done deleting route target %d", id)
+
+	return nil
+}
+
+// resourceNetboxIpamRouteTargetImport imports a route target by ID, either
+// "8" or "ipam/route-target/8".
+func resourceNetboxIpamRouteTargetImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/route-target")
+	if !ok {
+		return nil, fmt.Errorf("invalid route target ID %q, expected a number", d.Id())
+	}
+
+	return importedState(d, "ipam/route-target", "route_target_id", id)
+}
+
+// routeTargetBody returns the payload of a route target create or update
+// request.
+func routeTargetBody(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	body := map[string]interface{}{
+		"name":        d.Get("name").(string),
+		"tenant":      nil,
+		"description": d.Get("description").(string),
+		"tags":        expandTags(d, meta),
+	}
+
+	if tenantID := d.Get("tenant_id").(int); tenantID != 0 {
+		body["tenant"] = tenantID
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	if customFields != nil {
+		body["custom_fields"] = customFields
+	}
+
+	return body, nil
+}
+
+// routeTargetIDs returns the IDs of nested route targets.
+func routeTargetIDs(routeTargets []nestedRouteTarget) []int {
+	var ids []int
+	for _, routeTarget := range routeTargets {
+		ids = append(ids, int(routeTarget.ID))
+	}
+	return ids
+}
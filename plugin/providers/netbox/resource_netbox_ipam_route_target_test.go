@@ -0,0 +1,130 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamRouteTarget_create(t *testing.T) {
+	server := newTestNetboxServer(t, "2.10", map[string]testRoute{
+		"POST /api/ipam/route-targets/": {status: http.StatusCreated, body: `{"id": 8, "name": "65000:100"}`},
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamRouteTarget().Schema, map[string]interface{}{
+		"name":      "65000:100",
+		"tenant_id": 7,
+	})
+
+	if err := resourceNetboxIpamRouteTargetCreate(d, server.meta(t)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "ipam/route-target/8" || d.Get("route_target_id") != 8 {
+		t.Fatalf("unexpected ID %q and route_target_id %v", d.Id(), d.Get("route_target_id"))
+	}
+
+	var sent map[string]interface{}
+	if err := json.Unmarshal([]byte(server.received()[0].body), &sent); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"name":        "65000:100",
+		"tenant":      float64(7),
+		"description": "",
+		"tags":        []interface{}{},
+	}
+	if !reflect.DeepEqual(sent, expected) {
+		t.Fatalf("expected %v to be sent, got %v", expected, sent)
+	}
+}
+
+func TestResourceNetboxIpamRouteTarget_unsupported(t *testing.T) {
+	server := newTestNetboxServer(t, "2.9", map[string]testRoute{})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamRouteTarget().Schema, map[string]interface{}{
+		"name": "65000:100",
+	})
+
+	err := resourceNetboxIpamRouteTargetCreate(d, server.meta(t))
+	expected := "netbox_ipam_route_target requires Netbox API >= 2.10 (route targets), but the server runs Netbox API 2.9"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got %v", expected, err)
+	}
+	if len(server.received()) != 0 {
+		t.Fatalf("expected no request, got %v", server.received())
+	}
+}
+
+func TestResourceNetboxIpamVrfDomain_routeTargets(t *testing.T) {
+	cases := []struct {
+		version  string
+		raw      map[string]interface{}
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			version:  "2.10",
+			raw:      map[string]interface{}{"import_targets": []interface{}{8, 9}},
+			expected: map[string]interface{}{"import_targets": []interface{}{float64(8), float64(9)}, "export_targets": []interface{}{}},
+		},
+		{
+			version:  "2.5",
+			raw:      map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			version: "2.9",
+			raw:     map[string]interface{}{"export_targets": []interface{}{8}},
+			err:     "export_targets requires Netbox API >= 2.10 (route targets), but the server runs Netbox API 2.9",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.version, func(t *testing.T) {
+			server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+				"POST /api/ipam/vrfs/": {status: http.StatusCreated, body: `{"id": 3}`},
+			})
+			defer server.Close()
+
+			raw := map[string]interface{}{
+				"name":                "Toni Kensa",
+				"route_distinguisher": "toni-kensa-west",
+			}
+			for name, value := range tc.raw {
+				raw[name] = value
+			}
+
+			d := schema.TestResourceDataRaw(t, resourceNetboxIpamVrfDomain().Schema, raw)
+
+			err := resourceNetboxIpamVrfDomainCreate(d, server.meta(t))
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				if len(server.received()) != 0 {
+					t.Fatalf("expected no request, got %v", server.received())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			var sent map[string]interface{}
+			if err := json.Unmarshal([]byte(server.received()[0].body), &sent); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			for _, name := range []string{"import_targets", "export_targets"} {
+				value, ok := tc.expected[name]
+				if actual, sentOk := sent[name]; ok != sentOk || !reflect.DeepEqual(actual, value) {
+					t.Errorf("expected %s to be sent as %#v, got %#v", name, value, actual)
+				}
+			}
+		})
+	}
+}
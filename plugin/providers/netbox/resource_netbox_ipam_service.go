@@ -0,0 +1,330 @@
+package netbox
+
+import (
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceNetboxIpamService is the core Terraform resource structure for the netbox_ipam_service resource.
+//
+// The pinned client expects IP address IDs where Netbox returns nested IP
+// addresses, so services are managed through doJSON.
+func resourceNetboxIpamService() *schema.Resource {
+	return upgradeResourceID(&schema.Resource{
+		Create: resourceNetboxIpamServiceCreate,
+		Read:   resourceNetboxIpamServiceRead,
+		Update: resourceNetboxIpamServiceUpdate,
+		Delete: resourceNetboxIpamServiceDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetboxIpamServiceImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"service_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"protocol": &schema.Schema{
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     serviceProtocolChoices.validate,
+				DiffSuppressFunc: serviceProtocolChoices.suppressEquivalent,
+			},
+			"ports": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Ports the service listens on, Netbox before 2.10 only supports one.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntBetween(1, 65535),
+				},
+			},
+			"device_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"virtual_machine_id"},
+			},
+			"virtual_machine_id": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"device_id"},
+			},
+			"ip_address_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "IP addresses of the parent the service listens on, all of them when empty.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
+		},
+	}, "ipam/service", "service_id")
+}
+
+// servicePayload is a service as returned by Netbox, before or after 2.10.
+type servicePayload struct {
+	ID             int64                        `json:"id"`
+	Name           string                       `json:"name"`
+	Protocol       *models.ServiceProtocol      `json:"protocol"`
+	Port           *int64                       `json:"port"`
+	Ports          []int64                      `json:"ports"`
+	Device         *models.NestedDevice         `json:"device"`
+	VirtualMachine *models.NestedVirtualMachine `json:"virtual_machine"`
+	IPAddresses    []models.NestedIPAddress     `json:"ipaddresses"`
+	Description    string                       `json:"description"`
+	Tags           []string                     `json:"tags"`
+	CustomFields   interface{}                  `json:"custom_fields"`
+}
+
+// resourceNetboxIpamServiceCreate creates a new service in Netbox.
+func resourceNetboxIpamServiceCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	body, err := serviceBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Creating service in Netbox: %v", body)
+
+	var out servicePayload
+	if err := c.doJSON(http.MethodPost, "/ipam/services/", body, &out); err != nil {
+		log.Debugf("Failed to create service: %v", err)
+
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("ipam/service/%d", out.ID))
+	if err := d.Set("service_id", out.ID); err != nil {
+		return err
+	}
+
+	log.Debugf("Done creating service %d", out.ID)
+
+	return nil
+}
+
+// resourceNetboxIpamServiceUpdate applies updates to a service by ID when deltas are detected by Terraform.
+func resourceNetboxIpamServiceUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/service")
+	if err != nil {
+		return err
+	}
+
+	body, err := serviceBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Updating service %d in Netbox: %v", id, body)
+
+	if err := c.doJSON(http.MethodPut, fmt.Sprintf("/ipam/services/%d/", id), body, nil); err != nil {
+		log.Debugf("Failed to update service %d: %v", id, err)
+
+		return err
+	}
+
+	log.Debugf("Done updating service %d", id)
+
+	return nil
+}
+
+// resourceNetboxIpamServiceRead reads an existing service by ID.
+func resourceNetboxIpamServiceRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/service")
+	if err != nil {
+		return err
+	}
+
+	var service servicePayload
+
+	err = c.doJSON(http.MethodGet, fmt.Sprintf("/ipam/services/%d/", id), nil, &service)
+
+	if err != nil {
+		log.Debugf("Error fetching service ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("service_id", service.ID); err != nil {
+		return err
+	}
+	if err := d.Set("name", service.Name); err != nil {
+		return err
+	}
+
+	if service.Protocol != nil && service.Protocol.Value != nil {
+		if protocol, ok := serviceProtocolChoices.slug(*service.Protocol.Value); ok {
+			if err := d.Set("protocol", protocol); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := d.Set("ports", service.ports()); err != nil {
+		return err
+	}
+
+	var deviceID int64
+	if service.Device != nil {
+		deviceID = service.Device.ID
+	}
+	if err := d.Set("device_id", deviceID); err != nil {
+		return err
+	}
+
+	var virtualMachineID int64
+	if service.VirtualMachine != nil {
+		virtualMachineID = service.VirtualMachine.ID
+	}
+	if err := d.Set("virtual_machine_id", virtualMachineID); err != nil {
+		return err
+	}
+
+	if err := d.Set("ip_address_ids", service.ipAddressIDs()); err != nil {
+		return err
+	}
+	if err := d.Set("description", service.Description); err != nil {
+		return err
+	}
+	if err := d.Set("tags", flattenTags(d, meta, service.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, service.CustomFields)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceNetboxIpamServiceDelete deletes an existing service by ID.
+func resourceNetboxIpamServiceDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Debugf("Deleting service: %v\n", d)
+
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/service")
+	if err != nil {
+		return err
+	}
+
+	if err := c.doJSON(http.MethodDelete, fmt.Sprintf("/ipam/services/%d/", id), nil, nil); err != nil {
+		log.Debugf("Failed to delete service %d: %v", id, err)
+
+		return handleDeleteNotFound(err)
+	}
+
+	log.Debugf("Done deleting service %d", id)
+
+	return nil
+}
+
+// resourceNetboxIpamServiceImport imports a service by ID, either "12" or
+// "ipam/service/12".
+func resourceNetboxIpamServiceImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/service")
+	if !ok {
+		return nil, fmt.Errorf("invalid service ID %q, expected a number", d.Id())
+	}
+
+	return importedState(d, "ipam/service", "service_id", id)
+}
+
+// ports returns the ports of the service, whatever the Netbox version.
+func (s *servicePayload) ports() []int {
+	var ports []int
+	for _, port := range s.Ports {
+		ports = append(ports, int(port))
+	}
+	if len(ports) == 0 && s.Port != nil {
+		ports = append(ports, int(*s.Port))
+	}
+	return ports
+}
+
+func (s *servicePayload) ipAddressIDs() []int {
+	var ids []int
+	for _, ip := range s.IPAddresses {
+		ids = append(ids, int(ip.ID))
+	}
+	return ids
+}
+
+// serviceBody returns the payload of a service create or update request.
+func serviceBody(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	c := meta.(*ProviderNetboxClient)
+
+	protocol, err := serviceProtocolChoices.parse(d.Get("protocol").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	deviceID := d.Get("device_id").(int)
+	virtualMachineID := d.Get("virtual_machine_id").(int)
+	if deviceID == 0 && virtualMachineID == 0 {
+		return nil, fmt.Errorf("one of device_id or virtual_machine_id must be set")
+	}
+
+	ipAddressIDs := []int{}
+	for _, id := range d.Get("ip_address_ids").(*schema.Set).List() {
+		ipAddressIDs = append(ipAddressIDs, id.(int))
+	}
+
+	body := map[string]interface{}{
+		"name":            d.Get("name").(string),
+		"protocol":        protocol,
+		"device":          nil,
+		"virtual_machine": nil,
+		"ipaddresses":     ipAddressIDs,
+		"description":     d.Get("description").(string),
+		"tags":            expandTags(d, meta),
+	}
+
+	if deviceID != 0 {
+		body["device"] = deviceID
+	} else {
+		body["virtual_machine"] = virtualMachineID
+	}
+
+	ports := []int{}
+	for _, port := range d.Get("ports").(*schema.Set).List() {
+		ports = append(ports, port.(int))
+	}
+
+	switch {
+	case featureServicePorts.supportedBy(c.apiVersion()):
+		body["ports"] = ports
+	case len(ports) == 1:
+		body["port"] = ports[0]
+	default:
+		return nil, c.requireFeature(featureServicePorts, "ports with more than one port")
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	if customFields != nil {
+		body["custom_fields"] = customFields
+	}
+
+	return body, nil
+}
@@ -0,0 +1,130 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamService_create(t *testing.T) {
+	cases := []struct {
+		version  string
+		ports    []interface{}
+		expected map[string]interface{}
+	}{
+		{
+			"2.5",
+			[]interface{}{22},
+			map[string]interface{}{"protocol": float64(6), "port": float64(22)},
+		},
+		{
+			"2.10",
+			[]interface{}{80, 443},
+			map[string]interface{}{"protocol": "tcp", "ports": []interface{}{float64(80), float64(443)}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.version, func(t *testing.T) {
+			server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+				"POST /api/ipam/services/": {status: http.StatusCreated, body: `{"id": 12}`},
+			})
+			defer server.Close()
+
+			d := schema.TestResourceDataRaw(t, resourceNetboxIpamService().Schema, map[string]interface{}{
+				"name":               "web",
+				"protocol":           "tcp",
+				"ports":              tc.ports,
+				"virtual_machine_id": 3,
+				"ip_address_ids":     []interface{}{10},
+			})
+
+			if err := resourceNetboxIpamServiceCreate(d, server.meta(t)); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if d.Id() != "ipam/service/12" || d.Get("service_id") != 12 {
+				t.Fatalf("unexpected ID %q and service_id %v", d.Id(), d.Get("service_id"))
+			}
+
+			var sent map[string]interface{}
+			if err := json.Unmarshal([]byte(server.received()[0].body), &sent); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			expected := map[string]interface{}{
+				"name":            "web",
+				"device":          nil,
+				"virtual_machine": float64(3),
+				"ipaddresses":     []interface{}{float64(10)},
+				"description":     "",
+			}
+			for name, value := range tc.expected {
+				expected[name] = value
+			}
+			for name, value := range expected {
+				if actual, ok := sent[name]; !ok || !reflect.DeepEqual(actual, value) {
+					t.Errorf("expected %s to be sent as %#v, got %#v", name, value, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceNetboxIpamService_invalid(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{"name": "web", "protocol": "tcp", "ports": []interface{}{80, 443}, "device_id": 6},
+			"ports with more than one port requires Netbox API >= 2.10 (multiple service ports), but the server runs Netbox API 2.5",
+		},
+		{
+			map[string]interface{}{"name": "web", "protocol": "tcp", "ports": []interface{}{80}},
+			"one of device_id or virtual_machine_id must be set",
+		},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceNetboxIpamService().Schema, tc.raw)
+
+		if err := resourceNetboxIpamServiceCreate(d, meta); err == nil || err.Error() != tc.expected {
+			t.Errorf("%v: expected error %q, got %v", tc.raw, tc.expected, err)
+		}
+	}
+
+	if len(server.received()) != 0 {
+		t.Fatalf("expected no request, got %v", server.received())
+	}
+}
+
+func TestResourceNetboxIpamService_delete(t *testing.T) {
+	server := newTestNetboxServer(t, "2.10", map[string]testRoute{
+		"DELETE /api/ipam/services/12/": {status: http.StatusNoContent},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	for _, id := range []string{"ipam/service/12", "ipam/service/13"} {
+		d := resourceNetboxIpamService().TestResourceData()
+		d.SetId(id)
+
+		// Services already gone are not an error.
+		if err := resourceNetboxIpamServiceDelete(d, meta); err != nil {
+			t.Fatalf("%s: err: %s", id, err)
+		}
+	}
+
+	if requests := server.received(); len(requests) != 2 || requests[0].method != http.MethodDelete {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+}
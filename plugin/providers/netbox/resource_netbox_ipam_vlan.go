@@ -0,0 +1,355 @@
+package netbox
+
+import (
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceNetboxIpamVlan is the core Terraform resource structure for the netbox_ipam_vlan resource.
+//
+// The pinned client writes VLANs with the nested objects it reads back, which
+// Netbox rejects, so VLANs are created and updated through doJSON.
+func resourceNetboxIpamVlan() *schema.Resource {
+	return upgradeResourceID(&schema.Resource{
+		Create: resourceNetboxIpamVlanCreate,
+		Read:   resourceNetboxIpamVlanRead,
+		Update: resourceNetboxIpamVlanUpdate,
+		Delete: resourceNetboxIpamVlanDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetboxIpamVlanImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vid": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"allocate_vid"},
+				ValidateFunc:  validation.IntBetween(1, 4094),
+			},
+			"allocate_vid": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"vid"},
+				Description:   "Picks the lowest VID of the VLAN group unused between min and max when the VLAN is created. The VID is kept afterwards.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"min": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntBetween(1, 4094),
+						},
+						"max": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      4094,
+							ValidateFunc: validation.IntBetween(1, 4094),
+						},
+					},
+				},
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"vlan_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "active",
+				ValidateFunc:     vlanStatusChoices.validate,
+				DiffSuppressFunc: vlanStatusChoices.suppressEquivalent,
+			},
+			"site_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"group_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"role_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
+		},
+	}, "ipam/vlan", "vlan_id")
+}
+
+// resourceNetboxIpamVlanCreate creates a new VLAN in Netbox.
+func resourceNetboxIpamVlanCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	body, err := vlanBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// VLANs of a group are created one at a time, so an allocated VID is not
+	// taken by another VLAN of the same apply in the meantime.
+	if groupID := d.Get("group_id").(int); groupID != 0 {
+		lockKey := allocationLockKey("ipam/vlan-group", groupID)
+		netboxMutexKV.Lock(lockKey)
+		defer netboxMutexKV.Unlock(lockKey)
+	}
+
+	if allocations := d.Get("allocate_vid").([]interface{}); len(allocations) > 0 {
+		vid, err := vlanAllocateVID(d, meta, allocations[0])
+		if err != nil {
+			return err
+		}
+		body["vid"] = vid
+	} else if body["vid"] == 0 {
+		return fmt.Errorf("one of vid or allocate_vid must be set")
+	}
+
+	log.Debugf("Creating VLAN in Netbox: %v", body)
+
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.doJSON(http.MethodPost, "/ipam/vlans/", body, &out); err != nil {
+		log.Debugf("Failed to create VLAN: %v", err)
+
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("ipam/vlan/%d", out.ID))
+	if err := d.Set("vlan_id", out.ID); err != nil {
+		return err
+	}
+	if err := d.Set("vid", body["vid"]); err != nil {
+		return err
+	}
+
+	log.Debugf("Done creating VLAN %d", out.ID)
+
+	return nil
+}
+
+// resourceNetboxIpamVlanUpdate applies updates to a VLAN by ID when deltas are detected by Terraform.
+func resourceNetboxIpamVlanUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/vlan")
+	if err != nil {
+		return err
+	}
+
+	body, err := vlanBody(d, meta)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Updating VLAN %d in Netbox: %v", id, body)
+
+	if err := c.doJSON(http.MethodPut, fmt.Sprintf("/ipam/vlans/%d/", id), body, nil); err != nil {
+		log.Debugf("Failed to update VLAN %d: %v", id, err)
+
+		return err
+	}
+
+	log.Debugf("Done updating VLAN %d", id)
+
+	return nil
+}
+
+// resourceNetboxIpamVlanRead reads an existing VLAN by ID.
+func resourceNetboxIpamVlanRead(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	id, err := netboxID(d, "ipam/vlan")
+	if err != nil {
+		return err
+	}
+
+	var readParams = ipam.NewIPAMVlansReadParams().WithID(id)
+
+	readResult, err := netboxClient.IPAM.IPAMVlansRead(readParams, nil)
+
+	if err != nil {
+		log.Debugf("Error fetching VLAN ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	vlan := readResult.Payload
+
+	if err := d.Set("vlan_id", vlan.ID); err != nil {
+		return err
+	}
+	if err := d.Set("vid", vlan.Vid); err != nil {
+		return err
+	}
+	if err := d.Set("name", vlan.Name); err != nil {
+		return err
+	}
+	if err := d.Set("description", vlan.Description); err != nil {
+		return err
+	}
+
+	var siteID int64
+	if vlan.Site != nil {
+		siteID = vlan.Site.ID
+	}
+	if err := d.Set("site_id", siteID); err != nil {
+		return err
+	}
+
+	var groupID int64
+	if vlan.Group != nil {
+		groupID = vlan.Group.ID
+	}
+	if err := d.Set("group_id", groupID); err != nil {
+		return err
+	}
+
+	var tenantID int64
+	if vlan.Tenant != nil {
+		tenantID = vlan.Tenant.ID
+	}
+	if err := d.Set("tenant_id", tenantID); err != nil {
+		return err
+	}
+
+	var roleID int64
+	if vlan.Role != nil {
+		roleID = vlan.Role.ID
+	}
+	if err := d.Set("role_id", roleID); err != nil {
+		return err
+	}
+
+	if vlan.Status != nil && vlan.Status.Value != nil {
+		if status, ok := vlanStatusChoices.slug(*vlan.Status.Value); ok {
+			if err := d.Set("status", status); err != nil {
+				return err
+			}
+		}
+	}
+	if err := d.Set("tags", flattenTags(d, meta, vlan.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, vlan.CustomFields)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceNetboxIpamVlanDelete deletes an existing VLAN by ID.
+func resourceNetboxIpamVlanDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Debugf("Deleting VLAN: %v\n", d)
+
+	id, err := netboxID(d, "ipam/vlan")
+	if err != nil {
+		return err
+	}
+
+	var deleteParameters = ipam.NewIPAMVlansDeleteParams().WithID(id)
+
+	c := meta.(*ProviderNetboxClient).client
+
+	out, err := c.IPAM.IPAMVlansDelete(deleteParameters, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMVlansDelete: %v", err)
+
+		return handleDeleteNotFound(err)
+	}
+
+	log.Debugf("Done Executing IPAMVlansDelete: %v", out)
+
+	return nil
+}
+
+// resourceNetboxIpamVlanImport imports a VLAN by ID, either "42" or
+// "ipam/vlan/42".
+func resourceNetboxIpamVlanImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/vlan")
+	if !ok {
+		return nil, fmt.Errorf("invalid VLAN ID %q, expected a number", d.Id())
+	}
+
+	return importedState(d, "ipam/vlan", "vlan_id", id)
+}
+
+// vlanAllocateVID returns the VID picked for a new VLAN according to an
+// allocate_vid block.
+func vlanAllocateVID(d *schema.ResourceData, meta interface{}, raw interface{}) (int, error) {
+	groupID := d.Get("group_id").(int)
+	if groupID == 0 {
+		return 0, fmt.Errorf("allocate_vid requires group_id")
+	}
+
+	// An empty block has a nil element and the default range.
+	min, max := 1, 4094
+	if allocation, ok := raw.(map[string]interface{}); ok {
+		min, max = allocation["min"].(int), allocation["max"].(int)
+	}
+	if min > max {
+		return 0, fmt.Errorf("allocate_vid: min (%d) is greater than max (%d)", min, max)
+	}
+
+	return nextAvailableVID(meta.(*ProviderNetboxClient).client, groupID, min, max)
+}
+
+// vlanBody returns the payload of a VLAN create or update request. Unset
+// relations are sent as null so an update clears them.
+func vlanBody(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	status, err := vlanStatusChoices.parse(d.Get("status").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"vid":         d.Get("vid").(int),
+		"name":        d.Get("name").(string),
+		"status":      status,
+		"description": d.Get("description").(string),
+		"tags":        expandTags(d, meta),
+	}
+
+	for attribute, field := range map[string]string{
+		"site_id":   "site",
+		"group_id":  "group",
+		"tenant_id": "tenant",
+		"role_id":   "role",
+	} {
+		if id := d.Get(attribute).(int); id != 0 {
+			body[field] = id
+		} else {
+			body[field] = nil
+		}
+	}
+
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	if customFields != nil {
+		body["custom_fields"] = customFields
+	}
+
+	return body, nil
+}
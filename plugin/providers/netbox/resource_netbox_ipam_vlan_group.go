@@ -0,0 +1,188 @@
+package netbox
+
+import (
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceNetboxIpamVlanGroup is the core Terraform resource structure for the netbox_ipam_vlan_group resource.
+//
+// Like VLANs, VLAN groups are written through doJSON as the pinned client
+// sends the site as a nested object.
+func resourceNetboxIpamVlanGroup() *schema.Resource {
+	return upgradeResourceID(&schema.Resource{
+		Create: resourceNetboxIpamVlanGroupCreate,
+		Read:   resourceNetboxIpamVlanGroupRead,
+		Update: resourceNetboxIpamVlanGroupUpdate,
+		Delete: resourceNetboxIpamVlanGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNetboxIpamVlanGroupImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vlan_group_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"slug": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"site_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+		},
+	}, "ipam/vlan-group", "vlan_group_id")
+}
+
+// resourceNetboxIpamVlanGroupCreate creates a new VLAN group in Netbox.
+func resourceNetboxIpamVlanGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	body := vlanGroupBody(d)
+
+	log.Debugf("Creating VLAN group in Netbox: %v", body)
+
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.doJSON(http.MethodPost, "/ipam/vlan-groups/", body, &out); err != nil {
+		log.Debugf("Failed to create VLAN group: %v", err)
+
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("ipam/vlan-group/%d", out.ID))
+	if err := d.Set("vlan_group_id", out.ID); err != nil {
+		return err
+	}
+
+	log.Debugf("Done creating VLAN group %d", out.ID)
+
+	return nil
+}
+
+// resourceNetboxIpamVlanGroupUpdate applies updates to a VLAN group by ID when deltas are detected by Terraform.
+func resourceNetboxIpamVlanGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
+
+	id, err := netboxID(d, "ipam/vlan-group")
+	if err != nil {
+		return err
+	}
+
+	body := vlanGroupBody(d)
+
+	log.Debugf("Updating VLAN group %d in Netbox: %v", id, body)
+
+	if err := c.doJSON(http.MethodPut, fmt.Sprintf("/ipam/vlan-groups/%d/", id), body, nil); err != nil {
+		log.Debugf("Failed to update VLAN group %d: %v", id, err)
+
+		return err
+	}
+
+	log.Debugf("Done updating VLAN group %d", id)
+
+	return nil
+}
+
+// resourceNetboxIpamVlanGroupRead reads an existing VLAN group by ID.
+func resourceNetboxIpamVlanGroupRead(d *schema.ResourceData, meta interface{}) error {
+	netboxClient := meta.(*ProviderNetboxClient).client
+
+	id, err := netboxID(d, "ipam/vlan-group")
+	if err != nil {
+		return err
+	}
+
+	var readParams = ipam.NewIPAMVlanGroupsReadParams().WithID(id)
+
+	readResult, err := netboxClient.IPAM.IPAMVlanGroupsRead(readParams, nil)
+
+	if err != nil {
+		log.Debugf("Error fetching VLAN group ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("vlan_group_id", readResult.Payload.ID); err != nil {
+		return err
+	}
+	if err := d.Set("name", readResult.Payload.Name); err != nil {
+		return err
+	}
+	if err := d.Set("slug", readResult.Payload.Slug); err != nil {
+		return err
+	}
+
+	var siteID int64
+	if readResult.Payload.Site != nil {
+		siteID = readResult.Payload.Site.ID
+	}
+	if err := d.Set("site_id", siteID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceNetboxIpamVlanGroupDelete deletes an existing VLAN group by ID.
+func resourceNetboxIpamVlanGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Debugf("Deleting VLAN group: %v\n", d)
+
+	id, err := netboxID(d, "ipam/vlan-group")
+	if err != nil {
+		return err
+	}
+
+	var deleteParameters = ipam.NewIPAMVlanGroupsDeleteParams().WithID(id)
+
+	c := meta.(*ProviderNetboxClient).client
+
+	out, err := c.IPAM.IPAMVlanGroupsDelete(deleteParameters, nil)
+
+	if err != nil {
+		log.Debugf("Failed to execute IPAMVlanGroupsDelete: %v", err)
+
+		return handleDeleteNotFound(err)
+	}
+
+	log.Debugf("Done Executing IPAMVlanGroupsDelete: %v", out)
+
+	return nil
+}
+
+// resourceNetboxIpamVlanGroupImport imports a VLAN group by ID, either "6" or
+// "ipam/vlan-group/6".
+func resourceNetboxIpamVlanGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/vlan-group")
+	if !ok {
+		return nil, fmt.Errorf("invalid VLAN group ID %q, expected a number", d.Id())
+	}
+
+	return importedState(d, "ipam/vlan-group", "vlan_group_id", id)
+}
+
+// vlanGroupBody returns the payload of a VLAN group create or update request.
+func vlanGroupBody(d *schema.ResourceData) map[string]interface{} {
+	body := map[string]interface{}{
+		"name": d.Get("name").(string),
+		"slug": d.Get("slug").(string),
+		"site": nil,
+	}
+
+	if siteID := d.Get("site_id").(int); siteID != 0 {
+		body["site"] = siteID
+	}
+
+	return body
+}
@@ -0,0 +1,56 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamVlanGroup_createAndUpdate(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"POST /api/ipam/vlan-groups/":  {status: http.StatusCreated, body: `{"id": 6}`},
+		"PUT /api/ipam/vlan-groups/6/": {body: `{"id": 6}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamVlanGroup().Schema, map[string]interface{}{
+		"name":    "Plaza",
+		"slug":    "plaza",
+		"site_id": 5,
+	})
+
+	if err := resourceNetboxIpamVlanGroupCreate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "ipam/vlan-group/6" || d.Get("vlan_group_id") != 6 {
+		t.Fatalf("unexpected ID %q and vlan_group_id %v", d.Id(), d.Get("vlan_group_id"))
+	}
+
+	// Moving the group off its site clears the relation.
+	if err := d.Set("site_id", 0); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := resourceNetboxIpamVlanGroupUpdate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	requests := server.received()
+	expected := []map[string]interface{}{
+		{"name": "Plaza", "slug": "plaza", "site": float64(5)},
+		{"name": "Plaza", "slug": "plaza", "site": nil},
+	}
+	for i, request := range requests {
+		var sent map[string]interface{}
+		if err := json.Unmarshal([]byte(request.body), &sent); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(sent, expected[i]) {
+			t.Errorf("%s %s: expected %v to be sent, got %v", request.method, request.path, expected[i], sent)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package netbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxIpamVlan_createAndUpdate(t *testing.T) {
+	cases := []struct {
+		version string
+		status  interface{}
+		tags    interface{}
+	}{
+		{"2.5", float64(2), []interface{}{"web"}},
+		{"2.9", "reserved", []interface{}{map[string]interface{}{"name": "web"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.version, func(t *testing.T) {
+			server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+				"POST /api/ipam/vlans/":  {status: http.StatusCreated, body: `{"id": 9}`},
+				"PUT /api/ipam/vlans/9/": {body: `{"id": 9}`},
+			})
+			defer server.Close()
+
+			meta := server.meta(t)
+
+			d := schema.TestResourceDataRaw(t, resourceNetboxIpamVlan().Schema, map[string]interface{}{
+				"vid":      16,
+				"name":     "VLAN-16",
+				"status":   "reserved",
+				"group_id": 6,
+				"tags":     []interface{}{"web"},
+			})
+
+			if err := resourceNetboxIpamVlanCreate(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if d.Id() != "ipam/vlan/9" || d.Get("vlan_id") != 9 {
+				t.Fatalf("unexpected ID %q and vlan_id %v", d.Id(), d.Get("vlan_id"))
+			}
+
+			if err := resourceNetboxIpamVlanUpdate(d, meta); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			requests := server.received()
+			if len(requests) != 2 {
+				t.Fatalf("expected a create and an update, got %v", requests)
+			}
+
+			expected := map[string]interface{}{
+				"vid":         float64(16),
+				"name":        "VLAN-16",
+				"status":      tc.status,
+				"description": "",
+				"tags":        tc.tags,
+				"group":       float64(6),
+				"site":        nil,
+				"tenant":      nil,
+				"role":        nil,
+			}
+			for _, request := range requests {
+				var sent map[string]interface{}
+				if err := json.Unmarshal([]byte(request.body), &sent); err != nil {
+					t.Fatalf("err: %s", err)
+				}
+				if !reflect.DeepEqual(sent, expected) {
+					t.Errorf("%s %s: expected %v to be sent, got %v", request.method, request.path, expected, sent)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceNetboxIpamVlan_vidValidation(t *testing.T) {
+	s := resourceNetboxIpamVlan().Schema["vid"]
+
+	for _, vid := range []int{1, 4094} {
+		if _, errs := s.ValidateFunc(vid, "vid"); len(errs) > 0 {
+			t.Errorf("expected VID %d to be valid, got %v", vid, errs)
+		}
+	}
+
+	for _, vid := range []int{0, 4095} {
+		if _, errs := s.ValidateFunc(vid, "vid"); len(errs) == 0 {
+			t.Errorf("expected VID %d to be rejected", vid)
+		}
+	}
+}
+
+func TestResourceNetboxIpamVlan_import(t *testing.T) {
+	for _, id := range []string{"9", "ipam/vlan/9"} {
+		d := resourceNetboxIpamVlan().TestResourceData()
+		d.SetId(id)
+
+		imported, err := resourceNetboxIpamVlanImport(d, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if imported[0].Id() != "ipam/vlan/9" || imported[0].Get("vlan_id") != 9 {
+			t.Fatalf("unexpected import of %q: %s, vlan_id %v", id, imported[0].Id(), imported[0].Get("vlan_id"))
+		}
+	}
+
+	d := resourceNetboxIpamVlan().TestResourceData()
+	d.SetId("VLAN-16")
+	if _, err := resourceNetboxIpamVlanImport(d, nil); err == nil {
+		t.Fatalf("expected an invalid ID error")
+	}
+}
+
+// testVlanGroupServer serves the VLANs of group 6, recording the created ones.
+func testVlanGroupServer(t *testing.T, vids ...int) *testNetboxServer {
+	var mu sync.Mutex
+	vlans := append([]int{}, vids...)
+
+	list := func(r testRequest) testRoute {
+		u, _ := url.Parse(r.path)
+		offset, _ := strconv.Atoi(u.Query().Get("offset"))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Pages of two, whatever the limit, to exercise paging.
+		var results []string
+		for i := offset; i < len(vlans) && i < offset+2; i++ {
+			results = append(results, fmt.Sprintf(`{"id": %d, "vid": %d, "name": "VLAN-%d"}`, i+1, vlans[i], vlans[i]))
+		}
+		return testRoute{body: fmt.Sprintf(`{"count": %d, "results": [%s]}`, len(vlans), strings.Join(results, ", "))}
+	}
+
+	create := func(r testRequest) testRoute {
+		var sent struct {
+			Vid int `json:"vid"`
+		}
+		if err := json.Unmarshal([]byte(r.body), &sent); err != nil {
+			return testRoute{status: http.StatusBadRequest, body: `{"detail": "invalid JSON"}`}
+		}
+
+		// Netbox looks at the group before creating, without any locking.
+		mu.Lock()
+		taken := false
+		for _, vid := range vlans {
+			taken = taken || vid == sent.Vid
+		}
+		mu.Unlock()
+		if taken {
+			return testRoute{status: http.StatusBadRequest, body: `{"__all__": ["VLAN with this Group and VID already exists."]}`}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		vlans = append(vlans, sent.Vid)
+		return testRoute{status: http.StatusCreated, body: fmt.Sprintf(`{"id": %d}`, len(vlans))}
+	}
+
+	return newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"GET /api/ipam/vlans/":  {handler: list},
+		"POST /api/ipam/vlans/": {handler: create},
+	})
+}
+
+func TestResourceNetboxIpamVlan_allocateVID(t *testing.T) {
+	server := testVlanGroupServer(t, 100, 101, 103, 104, 200)
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamVlan().Schema, map[string]interface{}{
+		"name":         "app",
+		"group_id":     6,
+		"allocate_vid": []interface{}{map[string]interface{}{"min": 100, "max": 199}},
+	})
+
+	if err := resourceNetboxIpamVlanCreate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if vid := d.Get("vid"); vid != 102 {
+		t.Fatalf("expected VID 102, got %v", vid)
+	}
+
+	requests := server.received()
+	if last := requests[len(requests)-2].path; last != "/api/ipam/vlans/?group_id=6&limit=1000&offset=4" {
+		t.Fatalf("expected the group to be listed page by page, got %s", last)
+	}
+}
+
+func TestResourceNetboxIpamVlan_allocateVIDConcurrent(t *testing.T) {
+	server := testVlanGroupServer(t, 10)
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	var resources []*schema.ResourceData
+	for i := 0; i < 5; i++ {
+		resources = append(resources, schema.TestResourceDataRaw(t, resourceNetboxIpamVlan().Schema, map[string]interface{}{
+			"name":         fmt.Sprintf("app-%d", i),
+			"group_id":     6,
+			"allocate_vid": []interface{}{map[string]interface{}{"min": 10, "max": 20}},
+		}))
+	}
+	// A VLAN with a fixed VID in the same group is created alongside.
+	resources = append(resources, schema.TestResourceDataRaw(t, resourceNetboxIpamVlan().Schema, map[string]interface{}{
+		"name":     "fixed",
+		"vid":      20,
+		"group_id": 6,
+	}))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(resources))
+	for _, d := range resources {
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+			errs <- resourceNetboxIpamVlanCreate(d, meta)
+		}(d)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, d := range resources {
+		vid := d.Get("vid").(int)
+		if seen[vid] || vid < 11 || vid > 20 {
+			t.Fatalf("unexpected VIDs allocated: VID %d", vid)
+		}
+		seen[vid] = true
+	}
+}
+
+func TestResourceNetboxIpamVlan_allocateVIDErrors(t *testing.T) {
+	server := testVlanGroupServer(t, 10, 11)
+	defer server.Close()
+
+	meta := server.meta(t)
+
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{"name": "app"},
+			"one of vid or allocate_vid must be set",
+		},
+		{
+			map[string]interface{}{"name": "app", "allocate_vid": []interface{}{map[string]interface{}{}}},
+			"allocate_vid requires group_id",
+		},
+		{
+			map[string]interface{}{"name": "app", "group_id": 6, "allocate_vid": []interface{}{map[string]interface{}{"min": 20, "max": 10}}},
+			"allocate_vid: min (20) is greater than max (10)",
+		},
+		{
+			map[string]interface{}{"name": "app", "group_id": 6, "allocate_vid": []interface{}{map[string]interface{}{"min": 10, "max": 11}}},
+			"VLAN group 6 has no free VID between 10 and 11",
+		},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceNetboxIpamVlan().Schema, tc.raw)
+
+		if err := resourceNetboxIpamVlanCreate(d, meta); err == nil || err.Error() != tc.expected {
+			t.Errorf("%v: expected error %q, got %v", tc.raw, tc.expected, err)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package netbox
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	log "github.com/sirupsen/logrus"
 
@@ -12,13 +14,13 @@ import (
 
 // resourceNetboxIpamVrfDomain is the core Terraform resource structure for the netbox_ipam_vrf_domain resource.
 func resourceNetboxIpamVrfDomain() *schema.Resource {
-	return &schema.Resource{
+	return upgradeResourceID(&schema.Resource{
 		Create: resourceNetboxIpamVrfDomainCreate,
 		Read:   resourceNetboxIpamVrfDomainRead,
 		Update: resourceNetboxIpamVrfDomainUpdate,
 		Delete: resourceNetboxIpamVrfDomainDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNetboxIpamVrfDomainImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -47,8 +49,22 @@ func resourceNetboxIpamVrfDomain() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			"import_targets": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "IDs of the route targets imported by the VRF, Netbox 2.10 or later.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"export_targets": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "IDs of the route targets exported by the VRF, Netbox 2.10 or later.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
 		},
-	}
+	}, "ipam/vrf", "vrf_id")
 }
 
 // resourceNetboxIpamVrfDomainCreate creates a new VRF in Netbox.
@@ -61,14 +77,25 @@ func resourceNetboxIpamVrfDomainCreate(d *schema.ResourceData, meta interface{})
 	description := d.Get("description").(string)
 	tenantID := int64(d.Get("tenant_id").(int))
 
-	var parm = ipam.NewIPAMVrfsCreateParams().WithData(
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := vrfExplicitFields(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var parm = ipam.NewIPAMVrfsCreateParams().WithContext(ctx).WithData(
 		&models.VRFCreateUpdate{
 			Rd:            &routeDistinguisher,
 			Name:          &name,
 			Description:   description,
 			EnforceUnique: enforceUnique,
 			Tenant:        tenantID,
-			Tags:          []string{},
+			Tags:          expandTags(d, meta),
+			CustomFields:  customFields,
 		},
 	)
 
@@ -84,7 +111,9 @@ func resourceNetboxIpamVrfDomainCreate(d *schema.ResourceData, meta interface{})
 
 	// TODO Probably a better way to parse this ID
 	d.SetId(fmt.Sprintf("ipam/vrf/%d", out.Payload.ID))
-	d.Set("vrf_id", out.Payload.ID)
+	if err := d.Set("vrf_id", out.Payload.ID); err != nil {
+		return err
+	}
 
 	log.Debugf("Done Executing IPAMVrfsCreate: %v", out)
 
@@ -95,7 +124,10 @@ func resourceNetboxIpamVrfDomainCreate(d *schema.ResourceData, meta interface{})
 func resourceNetboxIpamVrfDomainUpdate(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	id := int64(d.Get("vrf_id").(int))
+	id, err := netboxID(d, "ipam/vrf")
+	if err != nil {
+		return err
+	}
 
 	name := d.Get("name").(string)
 	routeDistinguisher := d.Get("route_distinguisher").(string)
@@ -103,8 +135,19 @@ func resourceNetboxIpamVrfDomainUpdate(d *schema.ResourceData, meta interface{})
 	description := d.Get("description").(string)
 	tenantID := int64(d.Get("tenant_id").(int))
 
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
+
+	ctx, err := vrfExplicitFields(d, meta)
+	if err != nil {
+		return err
+	}
+
 	var parm = ipam.NewIPAMVrfsUpdateParams().
 		WithID(id).
+		WithContext(ctx).
 		WithData(
 			&models.VRFCreateUpdate{
 				Rd:            &routeDistinguisher,
@@ -112,7 +155,8 @@ func resourceNetboxIpamVrfDomainUpdate(d *schema.ResourceData, meta interface{})
 				Description:   description,
 				EnforceUnique: enforceUnique,
 				Tenant:        tenantID,
-				Tags:          []string{},
+				Tags:          expandTags(d, meta),
+				CustomFields:  customFields,
 			},
 		)
 
@@ -133,38 +177,106 @@ func resourceNetboxIpamVrfDomainUpdate(d *schema.ResourceData, meta interface{})
 
 // resourceNetboxIpamVrfDomainRead reads an existing VRF by ID.
 func resourceNetboxIpamVrfDomainRead(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
+	c := meta.(*ProviderNetboxClient)
 
-	id := int64(d.Get("vrf_id").(int))
+	id, err := netboxID(d, "ipam/vrf")
+	if err != nil {
+		return err
+	}
 
-	var readParams = ipam.NewIPAMVrfsReadParams().WithID(id)
+	// Read through doJSON as the pinned client does not know about route targets.
+	var readResult struct {
+		Payload vrfPayload
+	}
 
-	readResult, err := netboxClient.IPAM.IPAMVrfsRead(readParams, nil)
+	err = c.doJSON(http.MethodGet, fmt.Sprintf("/ipam/vrfs/%d/", id), nil, &readResult.Payload)
 
 	if err != nil {
 		log.Debugf("Error fetching VRF ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("vrf_id", readResult.Payload.ID); err != nil {
 		return err
 	}
 
-	d.Set("name", readResult.Payload.Name)
-	d.Set("route_distinguisher", readResult.Payload.Rd)
-	d.Set("enforce_unique", readResult.Payload.EnforceUnique)
-	d.Set("description", readResult.Payload.Description)
+	if err := d.Set("name", readResult.Payload.Name); err != nil {
+		return err
+	}
+	if err := d.Set("route_distinguisher", readResult.Payload.Rd); err != nil {
+		return err
+	}
+	if err := d.Set("enforce_unique", readResult.Payload.EnforceUnique); err != nil {
+		return err
+	}
+	if err := d.Set("description", readResult.Payload.Description); err != nil {
+		return err
+	}
 
 	var tenantID int64
 	if readResult.Payload.Tenant != nil {
 		tenantID = readResult.Payload.Tenant.ID
 	}
-	d.Set("tenant_id", tenantID)
+	if err := d.Set("tenant_id", tenantID); err != nil {
+		return err
+	}
+	if err := d.Set("import_targets", routeTargetIDs(readResult.Payload.ImportTargets)); err != nil {
+		return err
+	}
+	if err := d.Set("export_targets", routeTargetIDs(readResult.Payload.ExportTargets)); err != nil {
+		return err
+	}
+	if err := d.Set("tags", flattenTags(d, meta, readResult.Payload.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, readResult.Payload.CustomFields)); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// vrfPayload is a VRF as read from Netbox, with the fields the pinned client
+// does not know about.
+type vrfPayload struct {
+	models.VRF
+
+	ImportTargets []nestedRouteTarget `json:"import_targets"`
+	ExportTargets []nestedRouteTarget `json:"export_targets"`
+}
+
+// vrfExplicitFields returns the request context sending the route targets,
+// which are left out for servers without them.
+func vrfExplicitFields(d *schema.ResourceData, meta interface{}) (context.Context, error) {
+	c := meta.(*ProviderNetboxClient)
+
+	importTargets := d.Get("import_targets").(*schema.Set).List()
+	exportTargets := d.Get("export_targets").(*schema.Set).List()
+
+	if !featureRouteTargets.supportedBy(c.apiVersion()) {
+		if len(importTargets) > 0 {
+			return nil, c.requireFeature(featureRouteTargets, "import_targets")
+		}
+		if len(exportTargets) > 0 {
+			return nil, c.requireFeature(featureRouteTargets, "export_targets")
+		}
+		return context.Background(), nil
+	}
+
+	return withExplicitFields(context.Background(), map[string]interface{}{
+		"import_targets": importTargets,
+		"export_targets": exportTargets,
+	}), nil
+}
+
 // resourceNetboxIpamVrfDomainDelete deletes an existing VRF by ID.
 func resourceNetboxIpamVrfDomainDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Debugf("Deleting VRF: %v\n", d)
 
-	id := int64(d.Get("vrf_id").(int))
+	id, err := netboxID(d, "ipam/vrf")
+	if err != nil {
+		return err
+	}
 
 	var deleteParameters = ipam.NewIPAMVrfsDeleteParams().WithID(id)
 
@@ -174,9 +286,26 @@ func resourceNetboxIpamVrfDomainDelete(d *schema.ResourceData, meta interface{})
 
 	if err != nil {
 		log.Debugf("Failed to execute IPAMVrfsDelete: %v", err)
+
+		return handleDeleteNotFound(err)
 	}
 
 	log.Debugf("Done Executing IPAMVrfsDelete: %v", out)
 
 	return nil
 }
+
+// resourceNetboxIpamVrfDomainImport imports a VRF by ID, either "3" or
+// "ipam/vrf/3", or by route distinguisher.
+func resourceNetboxIpamVrfDomainImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/vrf")
+	if !ok {
+		var err error
+		id, err = vrfIDByRD(meta.(*ProviderNetboxClient).client, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importedState(d, "ipam/vrf", "vrf_id", id)
+}
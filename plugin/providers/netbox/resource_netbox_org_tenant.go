@@ -12,13 +12,13 @@ import (
 
 // resourceNetboxOrgTenant is the core Terraform resource structure for the netbox_org_tenant resource.
 func resourceNetboxOrgTenant() *schema.Resource {
-	return &schema.Resource{
+	return upgradeResourceID(&schema.Resource{
 		Create: resourceNetboxOrgTenantCreate,
 		Read:   resourceNetboxOrgTenantRead,
 		Update: resourceNetboxOrgTenantUpdate,
 		Delete: resourceNetboxOrgTenantDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNetboxOrgTenantImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -46,8 +46,10 @@ func resourceNetboxOrgTenant() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			"tags":          tagsSchema(),
+			"custom_fields": customFieldsSchema(),
 		},
-	}
+	}, "org/tenant", "tenant_id")
 }
 
 // resourceNetboxOrgTenantCreate creates a new Prefix in Netbox.
@@ -60,14 +62,20 @@ func resourceNetboxOrgTenantCreate(d *schema.ResourceData, meta interface{}) err
 	comments := d.Get("comments").(string)
 	tenantGroupID := int64(d.Get("tenant_group_id").(int))
 
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
+
 	var parm = tenancy.NewTenancyTenantsCreateParams().WithData(
 		&models.TenantCreateUpdate{
-			Name:        &name,
-			Slug:        &slug,
-			Description: description,
-			Comments:    comments,
-			Tags:        []string{},
-			Group:       tenantGroupID,
+			Name:         &name,
+			Slug:         &slug,
+			Description:  description,
+			Comments:     comments,
+			Tags:         expandTags(d, meta),
+			CustomFields: customFields,
+			Group:        tenantGroupID,
 			// TODO Tenant Group
 		},
 	)
@@ -84,7 +92,9 @@ func resourceNetboxOrgTenantCreate(d *schema.ResourceData, meta interface{}) err
 
 	// TODO Probably a better way to parse this ID
 	d.SetId(fmt.Sprintf("org/tenant/%d", out.Payload.ID))
-	d.Set("tenant_id", out.Payload.ID)
+	if err := d.Set("tenant_id", out.Payload.ID); err != nil {
+		return err
+	}
 
 	log.Debugf("Done Executing TenancyTenantsCreate: %v", out)
 
@@ -95,7 +105,10 @@ func resourceNetboxOrgTenantCreate(d *schema.ResourceData, meta interface{}) err
 func resourceNetboxOrgTenantUpdate(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	id := int64(d.Get("tenant_id").(int))
+	id, err := netboxID(d, "org/tenant")
+	if err != nil {
+		return err
+	}
 
 	name := d.Get("name").(string)
 	slug := d.Get("slug").(string)
@@ -103,16 +116,22 @@ func resourceNetboxOrgTenantUpdate(d *schema.ResourceData, meta interface{}) err
 	comments := d.Get("comments").(string)
 	tenantGroupID := int64(d.Get("tenant_group_id").(int))
 
+	customFields, err := expandCustomFields(d, meta)
+	if err != nil {
+		return err
+	}
+
 	var parm = tenancy.NewTenancyTenantsUpdateParams().
 		WithID(id).
 		WithData(
 			&models.TenantCreateUpdate{
-				Name:        &name,
-				Slug:        &slug,
-				Description: description,
-				Comments:    comments,
-				Tags:        []string{},
-				Group:       tenantGroupID,
+				Name:         &name,
+				Slug:         &slug,
+				Description:  description,
+				Comments:     comments,
+				Tags:         expandTags(d, meta),
+				CustomFields: customFields,
+				Group:        tenantGroupID,
 			},
 		)
 
@@ -135,7 +154,10 @@ func resourceNetboxOrgTenantUpdate(d *schema.ResourceData, meta interface{}) err
 func resourceNetboxOrgTenantRead(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	id := int64(d.Get("tenant_id").(int))
+	id, err := netboxID(d, "org/tenant")
+	if err != nil {
+		return err
+	}
 
 	var readParams = tenancy.NewTenancyTenantsReadParams().WithID(id)
 
@@ -143,19 +165,39 @@ func resourceNetboxOrgTenantRead(d *schema.ResourceData, meta interface{}) error
 
 	if err != nil {
 		log.Debugf("Error fetching Tenant ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
+	}
+
+	if err := d.Set("tenant_id", readResult.Payload.ID); err != nil {
 		return err
 	}
 
-	d.Set("name", readResult.Payload.Name)
-	d.Set("slug", readResult.Payload.Slug)
-	d.Set("description", readResult.Payload.Description)
-	d.Set("comments", readResult.Payload.Comments)
+	if err := d.Set("name", readResult.Payload.Name); err != nil {
+		return err
+	}
+	if err := d.Set("slug", readResult.Payload.Slug); err != nil {
+		return err
+	}
+	if err := d.Set("description", readResult.Payload.Description); err != nil {
+		return err
+	}
+	if err := d.Set("comments", readResult.Payload.Comments); err != nil {
+		return err
+	}
 
 	var tenantGroupID int64
 	if readResult.Payload.Group != nil {
 		tenantGroupID = readResult.Payload.Group.ID
 	}
-	d.Set("tenant_group_id", tenantGroupID)
+	if err := d.Set("tenant_group_id", tenantGroupID); err != nil {
+		return err
+	}
+	if err := d.Set("tags", flattenTags(d, meta, readResult.Payload.Tags)); err != nil {
+		return err
+	}
+	if err := d.Set("custom_fields", flattenCustomFields(d, readResult.Payload.CustomFields)); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -164,7 +206,10 @@ func resourceNetboxOrgTenantRead(d *schema.ResourceData, meta interface{}) error
 func resourceNetboxOrgTenantDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Debugf("Deleting Prefix: %v\n", d)
 
-	id := int64(d.Get("tenant_id").(int))
+	id, err := netboxID(d, "org/tenant")
+	if err != nil {
+		return err
+	}
 
 	var deleteParameters = tenancy.NewTenancyTenantsDeleteParams().WithID(id)
 
@@ -174,9 +219,26 @@ func resourceNetboxOrgTenantDelete(d *schema.ResourceData, meta interface{}) err
 
 	if err != nil {
 		log.Debugf("Failed to execute OrgTenantesDelete: %v", err)
+
+		return handleDeleteNotFound(err)
 	}
 
 	log.Debugf("Done Executing OrgTenantesDelete: %v", out)
 
 	return nil
 }
+
+// resourceNetboxOrgTenantImport imports a tenant by ID, either "7" or
+// "org/tenant/7", or by slug.
+func resourceNetboxOrgTenantImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "org/tenant")
+	if !ok {
+		var err error
+		id, err = tenantIDBySlug(meta.(*ProviderNetboxClient).client, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importedState(d, "org/tenant", "tenant_id", id)
+}
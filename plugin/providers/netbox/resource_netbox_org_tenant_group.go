@@ -2,6 +2,7 @@ package netbox
 
 import (
 	"fmt"
+	"net/http"
 
 	log "github.com/sirupsen/logrus"
 
@@ -11,14 +12,18 @@ import (
 )
 
 // resourceNetboxOrgTenantGroup is the core Terraform resource structure for the netbox_org_tenant_group resource.
+//
+// Tenant groups gained a parent and a description in Netbox 2.10, after the
+// API spoken by the pinned client, so they are read and written through
+// doJSON.
 func resourceNetboxOrgTenantGroup() *schema.Resource {
-	return &schema.Resource{
+	return upgradeResourceID(&schema.Resource{
 		Create: resourceNetboxOrgTenantGroupCreate,
 		Read:   resourceNetboxOrgTenantGroupRead,
 		Update: resourceNetboxOrgTenantGroupUpdate,
 		Delete: resourceNetboxOrgTenantGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNetboxOrgTenantGroupImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -34,102 +39,114 @@ func resourceNetboxOrgTenantGroup() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"parent_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "ID of the parent tenant group, Netbox 2.10 and later.",
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the tenant group, Netbox 2.10 and later.",
+			},
 		},
-	}
+	}, "org/tenant-group", "tenant_group_id")
 }
 
-// resourceNetboxOrgTenantGroupCreate creates a new Prefix in Netbox.
-func resourceNetboxOrgTenantGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
-
-	name := d.Get("name").(string)
-	slug := d.Get("slug").(string)
+// tenantGroupPayload is a tenant group as returned by Netbox. The parent and
+// description are only returned by Netbox 2.10 and later.
+type tenantGroupPayload struct {
+	ID          int64                     `json:"id"`
+	Name        string                    `json:"name"`
+	Slug        string                    `json:"slug"`
+	Parent      *models.NestedTenantGroup `json:"parent"`
+	Description string                    `json:"description"`
+}
 
-	var parm = tenancy.NewTenancyTenantGroupsCreateParams().WithData(
-		&models.TenantGroup{
-			Name: &name,
-			Slug: &slug,
-		},
-	)
+// resourceNetboxOrgTenantGroupCreate creates a new tenant group in Netbox.
+func resourceNetboxOrgTenantGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderNetboxClient)
 
-	log.Debugf("Executing TenancyTenantGroupsCreate against Netbox: %v", parm)
+	body, err := tenantGroupBody(d, meta)
+	if err != nil {
+		return err
+	}
 
-	out, err := netboxClient.Tenancy.TenancyTenantGroupsCreate(parm, nil)
+	log.Debugf("Creating tenant group in Netbox: %v", body)
 
-	if err != nil {
-		log.Debugf("Failed to execute TenancyTenantGroupsCreate: %v", err)
+	var out tenantGroupPayload
+	if err := c.doJSON(http.MethodPost, "/tenancy/tenant-groups/", body, &out); err != nil {
+		log.Debugf("Failed to create tenant group: %v", err)
 
 		return err
 	}
 
-	// TODO Probably a better way to parse this ID
-	d.SetId(fmt.Sprintf("org/tenant-group/%d", out.Payload.ID))
-	d.Set("tenant_group_id", out.Payload.ID)
+	d.SetId(fmt.Sprintf("org/tenant-group/%d", out.ID))
+	if err := d.Set("tenant_group_id", out.ID); err != nil {
+		return err
+	}
 
-	log.Debugf("Done Executing TenancyTenantGroupsCreate: %v", out)
+	log.Debugf("Done creating tenant group %d", out.ID)
 
 	return nil
 }
 
-// resourceNetboxOrgTenantGroupUpdate applies updates to a Prefix by ID when deltas are detected by Terraform.
+// resourceNetboxOrgTenantGroupUpdate applies updates to a tenant group by ID when deltas are detected by Terraform.
 func resourceNetboxOrgTenantGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
-
-	id := int64(d.Get("tenant_group_id").(int))
-
-	name := d.Get("name").(string)
-	slug := d.Get("slug").(string)
+	c := meta.(*ProviderNetboxClient)
 
-	var parm = tenancy.NewTenancyTenantGroupsUpdateParams().
-		WithID(id).
-		WithData(
-			&models.TenantGroup{
-				Name: &name,
-				Slug: &slug,
-			},
-		)
+	id, err := netboxID(d, "org/tenant-group")
+	if err != nil {
+		return err
+	}
 
-	log.Debugf("Executing TenancyTenantGroupsUpdate against Netbox: %v", parm)
+	body, err := tenantGroupBody(d, meta)
+	if err != nil {
+		return err
+	}
 
-	out, err := netboxClient.Tenancy.TenancyTenantGroupsUpdate(parm, nil)
+	log.Debugf("Updating tenant group %d in Netbox: %v", id, body)
 
-	if err != nil {
-		log.Debugf("Failed to execute TenancyTenantGroupsUpdate: %v", err)
+	if err := c.doJSON(http.MethodPut, fmt.Sprintf("/tenancy/tenant-groups/%d/", id), body, nil); err != nil {
+		log.Debugf("Failed to update tenant group %d: %v", id, err)
 
 		return err
 	}
 
-	log.Debugf("Done Executing TenancyTenantGroupsUpdate: %v", out)
+	log.Debugf("Done updating tenant group %d", id)
 
 	return nil
 }
 
-// resourceNetboxOrgTenantGroupRead reads an existing Prefix by ID.
+// resourceNetboxOrgTenantGroupRead reads an existing tenant group by ID.
 func resourceNetboxOrgTenantGroupRead(d *schema.ResourceData, meta interface{}) error {
-	netboxClient := meta.(*ProviderNetboxClient).client
+	c := meta.(*ProviderNetboxClient)
 
-	id := int64(d.Get("tenant_group_id").(int))
+	id, err := netboxID(d, "org/tenant-group")
+	if err != nil {
+		return err
+	}
 
-	var readParams = tenancy.NewTenancyTenantGroupsReadParams().WithID(id)
+	var tenantGroup tenantGroupPayload
 
-	readResult, err := netboxClient.Tenancy.TenancyTenantGroupsRead(readParams, nil)
+	err = c.doJSON(http.MethodGet, fmt.Sprintf("/tenancy/tenant-groups/%d/", id), nil, &tenantGroup)
 
 	if err != nil {
 		log.Debugf("Error fetching TenantGroup ID # %d from Netbox = %v", id, err)
-		return err
+		return handleReadNotFound(d, err)
 	}
 
-	d.Set("name", readResult.Payload.Name)
-	d.Set("slug", readResult.Payload.Slug)
-
-	return nil
+	return setOrgTenantGroup(d, &tenantGroup)
 }
 
-// resourceNetboxOrgTenantGroupDelete deletes an existing Prefix by ID.
+// resourceNetboxOrgTenantGroupDelete deletes an existing tenant group by ID.
 func resourceNetboxOrgTenantGroupDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Debugf("Deleting TenantGroup: %v\n", d)
 
-	id := int64(d.Get("tenant_group_id").(int))
+	id, err := netboxID(d, "org/tenant-group")
+	if err != nil {
+		return err
+	}
 
 	var deleteParameters = tenancy.NewTenancyTenantGroupsDeleteParams().WithID(id)
 
@@ -139,9 +156,87 @@ func resourceNetboxOrgTenantGroupDelete(d *schema.ResourceData, meta interface{}
 
 	if err != nil {
 		log.Debugf("Failed to execute OrgTenantGroupsDelete: %v", err)
+
+		return handleDeleteNotFound(err)
 	}
 
 	log.Debugf("Done Executing OrgTenantGroupsDelete: %v", out)
 
 	return nil
 }
+
+// setOrgTenantGroup copies a tenant group returned by Netbox into the
+// resource or data source state.
+func setOrgTenantGroup(d *schema.ResourceData, tenantGroup *tenantGroupPayload) error {
+	if err := d.Set("tenant_group_id", tenantGroup.ID); err != nil {
+		return err
+	}
+	if err := d.Set("name", tenantGroup.Name); err != nil {
+		return err
+	}
+	if err := d.Set("slug", tenantGroup.Slug); err != nil {
+		return err
+	}
+
+	var parentID int64
+	if tenantGroup.Parent != nil {
+		parentID = tenantGroup.Parent.ID
+	}
+	if err := d.Set("parent_id", parentID); err != nil {
+		return err
+	}
+
+	if err := d.Set("description", tenantGroup.Description); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// tenantGroupBody returns the payload of a tenant group create or update
+// request. The parent and description are only sent to servers that know
+// them.
+func tenantGroupBody(d *schema.ResourceData, meta interface{}) (map[string]interface{}, error) {
+	c := meta.(*ProviderNetboxClient)
+
+	body := map[string]interface{}{
+		"name": d.Get("name").(string),
+		"slug": d.Get("slug").(string),
+	}
+
+	parentID := d.Get("parent_id").(int)
+	description := d.Get("description").(string)
+
+	if !featureTenantGroupHierarchy.supportedBy(c.apiVersion()) {
+		if parentID != 0 {
+			return nil, c.requireFeature(featureTenantGroupHierarchy, "parent_id")
+		}
+		if description != "" {
+			return nil, c.requireFeature(featureTenantGroupHierarchy, "description")
+		}
+		return body, nil
+	}
+
+	body["parent"] = nil
+	if parentID != 0 {
+		body["parent"] = parentID
+	}
+	body["description"] = description
+
+	return body, nil
+}
+
+// resourceNetboxOrgTenantGroupImport imports a tenant group by ID, either
+// "2" or "org/tenant-group/2", or by slug.
+func resourceNetboxOrgTenantGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "org/tenant-group")
+	if !ok {
+		var err error
+		id, err = tenantGroupIDBySlug(meta.(*ProviderNetboxClient).client, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importedState(d, "org/tenant-group", "tenant_group_id", id)
+}
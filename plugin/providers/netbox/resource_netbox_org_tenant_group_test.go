@@ -0,0 +1,91 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceNetboxOrgTenantGroup_create(t *testing.T) {
+	cases := []struct {
+		version  string
+		raw      map[string]interface{}
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			version: "2.10",
+			raw:     map[string]interface{}{"parent_id": 1, "description": "Tenants of the Splatoon games."},
+			expected: map[string]interface{}{
+				"name":        "Splatoon Tenants",
+				"slug":        "splatoon",
+				"parent":      float64(1),
+				"description": "Tenants of the Splatoon games.",
+			},
+		},
+		{
+			version: "2.10",
+			raw:     map[string]interface{}{},
+			expected: map[string]interface{}{
+				"name":        "Splatoon Tenants",
+				"slug":        "splatoon",
+				"parent":      nil,
+				"description": "",
+			},
+		},
+		{
+			version:  "2.5",
+			raw:      map[string]interface{}{},
+			expected: map[string]interface{}{"name": "Splatoon Tenants", "slug": "splatoon"},
+		},
+		{
+			version: "2.9",
+			raw:     map[string]interface{}{"parent_id": 1},
+			err:     "parent_id requires Netbox API >= 2.10 (nested tenant groups), but the server runs Netbox API 2.9",
+		},
+	}
+
+	for _, tc := range cases {
+		server := newTestNetboxServer(t, tc.version, map[string]testRoute{
+			"POST /api/tenancy/tenant-groups/": {status: http.StatusCreated, body: `{"id": 2}`},
+		})
+
+		raw := map[string]interface{}{"name": "Splatoon Tenants", "slug": "splatoon"}
+		for name, value := range tc.raw {
+			raw[name] = value
+		}
+
+		d := schema.TestResourceDataRaw(t, resourceNetboxOrgTenantGroup().Schema, raw)
+
+		err := resourceNetboxOrgTenantGroupCreate(d, server.meta(t))
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("%s %v: expected error %q, got %v", tc.version, tc.raw, tc.err, err)
+			}
+			if len(server.received()) != 0 {
+				t.Fatalf("%s %v: expected no request, got %v", tc.version, tc.raw, server.received())
+			}
+			server.Close()
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s %v: err: %s", tc.version, tc.raw, err)
+		}
+		if d.Id() != "org/tenant-group/2" || d.Get("tenant_group_id") != 2 {
+			t.Fatalf("%s %v: unexpected ID %q and tenant_group_id %v", tc.version, tc.raw, d.Id(), d.Get("tenant_group_id"))
+		}
+
+		var sent map[string]interface{}
+		if err := json.Unmarshal([]byte(server.received()[0].body), &sent); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !reflect.DeepEqual(sent, tc.expected) {
+			t.Fatalf("%s %v: expected %v to be sent, got %v", tc.version, tc.raw, tc.expected, sent)
+		}
+
+		server.Close()
+	}
+}
@@ -12,13 +12,13 @@ import (
 
 // resourceNetboxRegionalInternetRegistry is the core Terraform resource structure for the netbox_regional_internet_registry resource.
 func resourceNetboxRegionalInternetRegistry() *schema.Resource {
-	return &schema.Resource{
+	return upgradeResourceID(&schema.Resource{
 		Create: resourceNetboxRegionalInternetRegistryCreate,
 		Read:   resourceNetboxRegionalInternetRegistryRead,
 		Update: resourceNetboxRegionalInternetRegistryUpdate,
 		Delete: resourceNetboxRegionalInternetRegistryDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNetboxRegionalInternetRegistryImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -40,7 +40,7 @@ func resourceNetboxRegionalInternetRegistry() *schema.Resource {
 				Default:  false,
 			},
 		},
-	}
+	}, "ipam/rir", "rir_id")
 }
 
 // resourceNetboxRegionalInternetRegistryCreate creates a new RIR in Netbox.
@@ -71,7 +71,9 @@ func resourceNetboxRegionalInternetRegistryCreate(d *schema.ResourceData, meta i
 
 	// TODO Probably a better way to parse this ID
 	d.SetId(fmt.Sprintf("ipam/rir/%v", out.Payload.ID))
-	d.Set("rir_id", out.Payload.ID)
+	if err := d.Set("rir_id", out.Payload.ID); err != nil {
+		return err
+	}
 
 	log.Debugf("Done Executing IPAMRirsCreate: %v", out)
 
@@ -82,14 +84,16 @@ func resourceNetboxRegionalInternetRegistryCreate(d *schema.ResourceData, meta i
 func resourceNetboxRegionalInternetRegistryUpdate(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	//terraformID := d.Id()
-	netboxID := int64(d.Get("rir_id").(int))
+	id, err := netboxID(d, "ipam/rir")
+	if err != nil {
+		return err
+	}
 	name := d.Get("name").(string)
 	slug := d.Get("slug").(string)
 	isPrivate := d.Get("is_private").(bool)
 
 	var parm = ipam.NewIPAMRirsUpdateParams().
-		WithID(netboxID).
+		WithID(id).
 		WithData(
 			&models.RIR{
 				Slug:      &slug,
@@ -117,24 +121,34 @@ func resourceNetboxRegionalInternetRegistryUpdate(d *schema.ResourceData, meta i
 func resourceNetboxRegionalInternetRegistryRead(d *schema.ResourceData, meta interface{}) error {
 	netboxClient := meta.(*ProviderNetboxClient).client
 
-	//terraformID := d.Id()
-	netboxID := int64(d.Get("rir_id").(int))
+	id, err := netboxID(d, "ipam/rir")
+	if err != nil {
+		return err
+	}
 
-	var readParams = ipam.NewIPAMRirsReadParams().WithID(netboxID)
+	var readParams = ipam.NewIPAMRirsReadParams().WithID(id)
 
 	readRirResult, err := netboxClient.IPAM.IPAMRirsRead(readParams, nil)
 
 	if err != nil {
-		log.Debugf("Error fetching RIR ID # %d from Netbox = %v", netboxID, err)
-		return err
+		log.Debugf("Error fetching RIR ID # %d from Netbox = %v", id, err)
+		return handleReadNotFound(d, err)
 	}
 
-	log.Debugf("Read RIR %d = %v", netboxID, readRirResult.Payload)
+	log.Debugf("Read RIR %d = %v", id, readRirResult.Payload)
 
-	d.Set("name", readRirResult.Payload.Name)
-	d.Set("slug", readRirResult.Payload.Slug)
-	d.Set("is_private", readRirResult.Payload.IsPrivate)
-	d.Set("rir_id", readRirResult.Payload.ID)
+	if err := d.Set("name", readRirResult.Payload.Name); err != nil {
+		return err
+	}
+	if err := d.Set("slug", readRirResult.Payload.Slug); err != nil {
+		return err
+	}
+	if err := d.Set("is_private", readRirResult.Payload.IsPrivate); err != nil {
+		return err
+	}
+	if err := d.Set("rir_id", readRirResult.Payload.ID); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -143,9 +157,12 @@ func resourceNetboxRegionalInternetRegistryRead(d *schema.ResourceData, meta int
 func resourceNetboxRegionalInternetRegistryDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Debugf("Deleting RIR: %v\n", d)
 
-	netboxID := int64(d.Get("rir_id").(int))
+	id, err := netboxID(d, "ipam/rir")
+	if err != nil {
+		return err
+	}
 
-	var deleteParameters = ipam.NewIPAMRirsDeleteParams().WithID(netboxID)
+	var deleteParameters = ipam.NewIPAMRirsDeleteParams().WithID(id)
 
 	c := meta.(*ProviderNetboxClient).client
 
@@ -153,9 +170,26 @@ func resourceNetboxRegionalInternetRegistryDelete(d *schema.ResourceData, meta i
 
 	if err != nil {
 		log.Debugf("Failed to execute IPAMRirsDelete: %v", err)
+
+		return handleDeleteNotFound(err)
 	}
 
 	log.Debugf("Done Executing IPAMRirsDelete: %v", out)
 
 	return nil
 }
+
+// resourceNetboxRegionalInternetRegistryImport imports a RIR by ID, either
+// "1" or "ipam/rir/1", or by slug.
+func resourceNetboxRegionalInternetRegistryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, ok := importNumericID(d.Id(), "ipam/rir")
+	if !ok {
+		var err error
+		id, err = rirIDBySlug(meta.(*ProviderNetboxClient).client, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return importedState(d, "ipam/rir", "rir_id", id)
+}
@@ -0,0 +1,396 @@
+package netbox
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testResourceReads describes, for every resource, the state Read must
+// produce from a recorded payload. expected has to cover every attribute of
+// the schema, so new attributes cannot be left out of Read.
+var testResourceReads = []struct {
+	name     string
+	resource func() *schema.Resource
+	id       string
+	state    map[string]interface{}
+	routes   map[string]testRoute
+	expected map[string]interface{}
+}{
+	{
+		name:     "netbox_ipam_prefix",
+		resource: resourceNetboxIpamPrefix,
+		id:       "ipam/prefix/42",
+		state:    map[string]interface{}{"prefix_id": 42},
+		routes: map[string]testRoute{
+			"GET /api/ipam/prefixes/42/": {body: "ipam_prefix.json"},
+		},
+		expected: map[string]interface{}{
+			"prefix":        "192.168.100.0/24",
+			"prefix_id":     42,
+			"vrf_id":        3,
+			"tenant_id":     7,
+			"is_pool":       true,
+			"description":   "Toni Kensa West - Primary Network",
+			"status":        "reserved",
+			"role_id":       4,
+			"site_id":       5,
+			"vlan_id":       9,
+			"tags":          []interface{}{"web"},
+			"custom_fields": map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_available_prefix",
+		resource: resourceNetboxIpamAvailablePrefix,
+		id:       "ipam/prefix/42",
+		state:    map[string]interface{}{"prefix_id": 42, "parent_prefix_id": 1, "prefix_length": 24},
+		routes: map[string]testRoute{
+			"GET /api/ipam/prefixes/42/": {body: "ipam_prefix.json"},
+		},
+		expected: map[string]interface{}{
+			"parent_prefix_id": 1,
+			"parent_filter":    []interface{}{},
+			"prefix_length":    24,
+			"prefix":           "192.168.100.0/24",
+			"prefix_id":        42,
+			"vrf_id":           3,
+			"tenant_id":        7,
+			"is_pool":          true,
+			"description":      "Toni Kensa West - Primary Network",
+			"status":           "reserved",
+			"role_id":          4,
+			"site_id":          5,
+			"vlan_id":          9,
+			"tags":             []interface{}{"web"},
+			"custom_fields":    map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_ip_address",
+		resource: resourceNetboxIpamIPAddress,
+		id:       "ipam/ip-address/10",
+		state:    map[string]interface{}{"ip_address_id": 10},
+		routes: map[string]testRoute{
+			"GET /api/ipam/ip-addresses/10/": {body: "ipam_ip_address.json"},
+			"GET /api/dcim/devices/6/":       {body: `{"id": 6, "primary_ip4": {"id": 10}, "primary_ip6": null}`},
+		},
+		expected: map[string]interface{}{
+			"ip_address_id":             10,
+			"address":                   "192.168.100.1/24",
+			"vrf_id":                    3,
+			"tenant_id":                 7,
+			"status":                    2,
+			"role_id":                   41,
+			"description":               "Toni Kensa West Primary Router",
+			"nat_inside_ip_address_id":  11,
+			"nat_outside_ip_address_id": 12,
+			"dns_name":                  "router.tonikensa.splatnet",
+			"interface_id":              8,
+			"interface_type":            "dcim",
+			"primary_for_device":        true,
+			"primary_for_vm":            false,
+			"tags":                      []interface{}{"web"},
+			"custom_fields":             map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		// Choice objects may come back without a value.
+		name:     "netbox_ipam_ip_address",
+		resource: resourceNetboxIpamIPAddress,
+		id:       "ipam/ip-address/10",
+		state:    map[string]interface{}{"ip_address_id": 10},
+		routes: map[string]testRoute{
+			"GET /api/ipam/ip-addresses/10/": {body: "ipam_ip_address_null_choices.json"},
+		},
+		expected: map[string]interface{}{
+			"ip_address_id":             10,
+			"address":                   "192.168.100.1/24",
+			"vrf_id":                    3,
+			"tenant_id":                 7,
+			"status":                    0,
+			"role_id":                   0,
+			"description":               "Toni Kensa West Primary Router",
+			"nat_inside_ip_address_id":  0,
+			"nat_outside_ip_address_id": 0,
+			"dns_name":                  "router.tonikensa.splatnet",
+			"interface_id":              0,
+			"interface_type":            "",
+			"primary_for_device":        false,
+			"primary_for_vm":            false,
+			"tags":                      []interface{}{"web"},
+			"custom_fields":             map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_available_ip",
+		resource: resourceNetboxIpamAvailableIP,
+		id:       "ipam/ip-address/10",
+		state:    map[string]interface{}{"ip_address_id": 10, "prefix_id": 42},
+		routes: map[string]testRoute{
+			"GET /api/ipam/ip-addresses/10/": {body: "ipam_ip_address.json"},
+		},
+		expected: map[string]interface{}{
+			"prefix_id":     42,
+			"ip_address_id": 10,
+			"address":       "192.168.100.1/24",
+			"vrf_id":        3,
+			"tenant_id":     7,
+			"status":        "reserved",
+			"dns_name":      "router.tonikensa.splatnet",
+			"description":   "Toni Kensa West Primary Router",
+			"tags":          []interface{}{"web"},
+			"custom_fields": map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_vrf",
+		resource: resourceNetboxIpamVrfDomain,
+		id:       "ipam/vrf/3",
+		state:    map[string]interface{}{"vrf_id": 3},
+		routes: map[string]testRoute{
+			"GET /api/ipam/vrfs/3/": {body: "ipam_vrf.json"},
+		},
+		expected: map[string]interface{}{
+			"name":                "Toni Kensa GmbH Private Networks",
+			"route_distinguisher": "toni-kensa-west",
+			"enforce_unique":      true,
+			"description":         "Private networks",
+			"vrf_id":              3,
+			"tenant_id":           7,
+			"import_targets":      []interface{}{8, 9},
+			"export_targets":      []interface{}{8},
+			"tags":                []interface{}{"web"},
+			"custom_fields":       map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_aggregate",
+		resource: resourceNetboxIpamAggregate,
+		id:       "ipam/aggregate/2",
+		routes: map[string]testRoute{
+			"GET /api/ipam/aggregates/2/": {body: "ipam_aggregate.json"},
+		},
+		expected: map[string]interface{}{
+			"aggregate_id":  2,
+			"prefix":        "192.168.0.0/16",
+			"rir_id":        1,
+			"rir_slug":      "squidland",
+			"tenant_id":     0,
+			"date_added":    "2019-09-01",
+			"description":   "Squidland Splatnet",
+			"tags":          []interface{}{"web"},
+			"custom_fields": map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_vlan",
+		resource: resourceNetboxIpamVlan,
+		id:       "ipam/vlan/9",
+		state:    map[string]interface{}{"vlan_id": 9},
+		routes: map[string]testRoute{
+			"GET /api/ipam/vlans/9/": {body: "ipam_vlan.json"},
+		},
+		expected: map[string]interface{}{
+			"vlan_id":       9,
+			"vid":           16,
+			"allocate_vid":  []interface{}{},
+			"name":          "VLAN-16",
+			"status":        "reserved",
+			"site_id":       5,
+			"group_id":      6,
+			"tenant_id":     7,
+			"role_id":       4,
+			"description":   "Toni Kensa West - Servers",
+			"tags":          []interface{}{"web"},
+			"custom_fields": map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_vlan_group",
+		resource: resourceNetboxIpamVlanGroup,
+		id:       "ipam/vlan-group/6",
+		state:    map[string]interface{}{"vlan_group_id": 6},
+		routes: map[string]testRoute{
+			"GET /api/ipam/vlan-groups/6/": {body: "ipam_vlan_group.json"},
+		},
+		expected: map[string]interface{}{
+			"vlan_group_id": 6,
+			"name":          "Plaza",
+			"slug":          "plaza",
+			"site_id":       5,
+		},
+	},
+	{
+		name:     "netbox_ipam_role",
+		resource: resourceNetboxIpamRole,
+		id:       "ipam/role/4",
+		state:    map[string]interface{}{"role_id": 4},
+		routes: map[string]testRoute{
+			"GET /api/ipam/roles/4/": {body: "ipam_role.json"},
+		},
+		expected: map[string]interface{}{
+			"role_id": 4,
+			"name":    "Production",
+			"slug":    "production",
+			"weight":  100,
+		},
+	},
+	{
+		name:     "netbox_ipam_service",
+		resource: resourceNetboxIpamService,
+		id:       "ipam/service/12",
+		state:    map[string]interface{}{"service_id": 12},
+		routes: map[string]testRoute{
+			"GET /api/ipam/services/12/": {body: "ipam_service.json"},
+		},
+		expected: map[string]interface{}{
+			"service_id":         12,
+			"name":               "ssh",
+			"protocol":           "tcp",
+			"ports":              []interface{}{22},
+			"device_id":          6,
+			"virtual_machine_id": 0,
+			"ip_address_ids":     []interface{}{10},
+			"description":        "Management access",
+			"tags":               []interface{}{"web"},
+			"custom_fields":      map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_route_target",
+		resource: resourceNetboxIpamRouteTarget,
+		id:       "ipam/route-target/8",
+		state:    map[string]interface{}{"route_target_id": 8},
+		routes: map[string]testRoute{
+			"GET /api/ipam/route-targets/8/": {body: "ipam_route_target.json"},
+		},
+		expected: map[string]interface{}{
+			"route_target_id": 8,
+			"name":            "65000:100",
+			"tenant_id":       7,
+			"description":     "Toni Kensa West routes",
+			"tags":            []interface{}{"web"},
+			"custom_fields":   map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_ipam_rir",
+		resource: resourceNetboxRegionalInternetRegistry,
+		id:       "ipam/rir/1",
+		state:    map[string]interface{}{"rir_id": 1},
+		routes: map[string]testRoute{
+			"GET /api/ipam/rirs/1/": {body: "ipam_rir.json"},
+		},
+		expected: map[string]interface{}{
+			"rir_id":     1,
+			"name":       "Squidland IP Addressing Protectorate",
+			"slug":       "squidland",
+			"is_private": true,
+		},
+	},
+	{
+		name:     "netbox_org_tenant",
+		resource: resourceNetboxOrgTenant,
+		id:       "org/tenant/7",
+		state:    map[string]interface{}{"tenant_id": 7},
+		routes: map[string]testRoute{
+			"GET /api/tenancy/tenants/7/": {body: "org_tenant.json"},
+		},
+		expected: map[string]interface{}{
+			"name":            "Squid Kids",
+			"slug":            "squid-kids",
+			"tenant_id":       7,
+			"description":     "Squid kids only.",
+			"comments":        "This tenant reserved for squid kids only.",
+			"tenant_group_id": 2,
+			"tags":            []interface{}{"web"},
+			"custom_fields":   map[string]interface{}{"owner": "alice"},
+		},
+	},
+	{
+		name:     "netbox_org_tenant_group",
+		resource: resourceNetboxOrgTenantGroup,
+		id:       "org/tenant-group/2",
+		state:    map[string]interface{}{"tenant_group_id": 2},
+		routes: map[string]testRoute{
+			"GET /api/tenancy/tenant-groups/2/": {body: "org_tenant_group.json"},
+		},
+		expected: map[string]interface{}{
+			"name":            "Splatoon Tenants",
+			"slug":            "splatoon",
+			"tenant_group_id": 2,
+			"parent_id":       1,
+			"description":     "Tenants of the Splatoon games.",
+		},
+	},
+}
+
+func TestResourceRead_fixtures(t *testing.T) {
+	for _, tc := range testResourceReads {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.resource()
+
+			if _, ok := providerResources()[tc.name]; !ok {
+				t.Fatalf("%s is not registered with the provider", tc.name)
+			}
+
+			var missing []string
+			for name := range r.Schema {
+				if _, ok := tc.expected[name]; !ok {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				sort.Strings(missing)
+				t.Fatalf("expected values are missing for %v", missing)
+			}
+
+			// Bodies ending in .json are fixtures from testdata/read.
+			routes := map[string]testRoute{}
+			for key, route := range tc.routes {
+				if len(route.body) > 5 && route.body[len(route.body)-5:] == ".json" {
+					route.body = testFixture(t, "read", route.body)
+				}
+				routes[key] = route
+			}
+
+			server := newTestNetboxServer(t, "2.5", routes)
+			defer server.Close()
+
+			d := r.TestResourceData()
+			d.SetId(tc.id)
+			for name, value := range tc.state {
+				if err := d.Set(name, value); err != nil {
+					t.Fatalf("err: %s", err)
+				}
+			}
+			// Only configured custom fields are read back.
+			if _, ok := r.Schema["custom_fields"]; ok {
+				if err := d.Set("custom_fields", map[string]interface{}{"owner": "bob"}); err != nil {
+					t.Fatalf("err: %s", err)
+				}
+			}
+
+			if err := r.Read(d, server.meta(t)); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if d.Id() != tc.id {
+				t.Fatalf("expected ID %q to be kept, got %q", tc.id, d.Id())
+			}
+
+			for name, value := range tc.expected {
+				actual := d.Get(name)
+				if set, ok := actual.(*schema.Set); ok {
+					actual = set.List()
+				}
+				if !reflect.DeepEqual(actual, value) {
+					t.Errorf("expected %s to be %#v, got %#v", name, value, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package netbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	log "github.com/sirupsen/logrus"
+)
+
+// doJSON sends a request the generated client does not cover through the
+// same HTTP client, so it is retried, rate limited and translated for the
+// server version like any other. A non nil body is sent as JSON, and the JSON
+// response is decoded into out when not nil. Error responses are returned as
+// *runtime.APIError, as the generated client does. The path may carry a
+// query string.
+func (c *ProviderNetboxClient) doJSON(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	apiPath, query := path, ""
+	if i := strings.Index(path, "?"); i >= 0 {
+		apiPath, query = path[:i], path[i+1:]
+	}
+
+	req, err := http.NewRequest(method, c.endpoint.URL(apiPath), reader)
+	if err != nil {
+		return err
+	}
+	req.URL.RawQuery = query
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Token "+c.configuration.AppID)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Debugf("Executing %s %s against Netbox", method, path)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	payload, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return runtime.NewAPIError(method+" "+path, string(payload), resp.StatusCode)
+	}
+
+	if out == nil || len(payload) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(payload, out); err != nil {
+		return fmt.Errorf("decoding response of %s %s: %s", method, path, err)
+	}
+
+	return nil
+}
+
+// listPageSize is the number of objects requested per page by doJSONList.
+const listPageSize = 1000
+
+// doJSONList fetches every page of a list endpoint and returns the raw
+// results, for the caller to decode.
+func (c *ProviderNetboxClient) doJSONList(path string, query url.Values) ([]json.RawMessage, error) {
+	var results []json.RawMessage
+
+	params := url.Values{}
+	for name, values := range query {
+		params[name] = values
+	}
+	params.Set("limit", strconv.Itoa(listPageSize))
+
+	// Netbox may return less than asked for, the offset follows what came back.
+	for offset := 0; ; {
+		params.Set("offset", strconv.Itoa(offset))
+
+		var page struct {
+			Count   int               `json:"count"`
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := c.doJSON(http.MethodGet, path+"?"+params.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+
+		results = append(results, page.Results...)
+		offset += len(page.Results)
+
+		if len(page.Results) == 0 || offset >= page.Count {
+			return results, nil
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package netbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// retryTransport wraps the transport used by the Netbox runtime client and
+// retries requests that failed with a transient error. Every attempt is
+// bounded by its own timeout and attempts are spaced by an exponential backoff
+// with jitter.
+//
+// Requests that are not idempotent (POST, PATCH) are only replayed when Netbox
+// definitely did not act on them: the connection was never established or the
+// request was rejected by rate limiting.
+type retryTransport struct {
+	next http.RoundTripper
+
+	// Timeout applied to each individual attempt. Zero disables it.
+	timeout time.Duration
+
+	// Number of retries after the initial attempt.
+	maxRetries int
+
+	// Bounds of the exponential backoff between attempts.
+	waitMin time.Duration
+	waitMax time.Duration
+
+	// Rate and concurrency limits every attempt has to pass, may be nil.
+	limiter *requestLimiter
+
+	// sleep waits between attempts, it is swapped out by tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(next http.RoundTripper, timeout time.Duration, maxRetries int, waitMin, waitMax time.Duration, limiter *requestLimiter) *retryTransport {
+	return &retryTransport{
+		next:       next,
+		timeout:    timeout,
+		maxRetries: maxRetries,
+		waitMin:    waitMin,
+		waitMax:    waitMax,
+		limiter:    limiter,
+		sleep:      sleepContext,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	getBody, err := requestBodyFactory(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+
+		if getBody != nil {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = cloneRequest(req)
+			attemptReq.Body = body
+		}
+
+		resp, err := t.roundTripOnce(attemptReq)
+
+		if attempt >= t.maxRetries || req.Context().Err() != nil || !shouldRetry(req.Method, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+
+		log.WithFields(
+			log.Fields{
+				"method":  req.Method,
+				"url":     req.URL.String(),
+				"attempt": attempt + 1,
+				"wait":    wait,
+				"status":  responseStatus(resp),
+				"error":   err,
+			},
+		).Warn("Retrying Netbox request")
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleepContext waits for d, or until ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// roundTripOnce performs a single attempt once the limiter lets it through.
+// Time spent waiting on the limiter does not count towards the per-attempt
+// timeout.
+func (t *retryTransport) roundTripOnce(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.acquire(req.Context())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+	}
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+
+	if err != nil {
+		cancel()
+		release()
+		return nil, err
+	}
+
+	// The body is read after RoundTrip returns, so the timeout and the
+	// in-flight slot may only be released once the caller is done with it.
+	resp.Body = &releaseOnClose{
+		ReadCloser: resp.Body,
+		release: func() {
+			cancel()
+			release()
+		},
+	}
+
+	return resp, nil
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After
+// header sent by Netbox takes precedence as long as it is within waitMax.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if wait := time.Duration(seconds) * time.Second; wait <= t.waitMax {
+				return wait
+			}
+			return t.waitMax
+		}
+	}
+
+	wait := t.waitMin << uint(attempt)
+	if wait <= 0 || wait > t.waitMax {
+		wait = t.waitMax
+	}
+
+	// Jitter between half and the full backoff so parallel resources don't
+	// retry in lockstep.
+	half := int64(wait / 2)
+	if half <= 0 {
+		return wait
+	}
+
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// shouldRetry decides whether a failed attempt may be replayed.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	idempotent := isIdempotent(method)
+
+	if err != nil {
+		if isDialError(err) {
+			return true
+		}
+		return idempotent && (isConnectionReset(err) || isTimeout(err))
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isDialError reports whether the connection to Netbox could not be
+// established, in which case the request never left the client.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func responseStatus(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+// requestBodyFactory returns a function producing a fresh copy of the request
+// body for every attempt, or nil when the request has no body.
+func requestBodyFactory(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	payload, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(payload)), nil
+	}, nil
+}
+
+func cloneRequest(req *http.Request) *http.Request {
+	clone := req.WithContext(req.Context())
+	clone.Header = req.Header.Clone()
+	return clone
+}
@@ -0,0 +1,194 @@
+package netbox
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testRoundTripper replays a scripted sequence of responses and errors and
+// records the body received on every attempt.
+type testRoundTripper struct {
+	statuses []int
+	errors   []error
+	bodies   []string
+}
+
+func (rt *testRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempt := len(rt.bodies)
+
+	var body string
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		body = string(b)
+	}
+	rt.bodies = append(rt.bodies, body)
+
+	if attempt < len(rt.errors) && rt.errors[attempt] != nil {
+		return nil, rt.errors[attempt]
+	}
+
+	status := http.StatusOK
+	if attempt < len(rt.statuses) {
+		status = rt.statuses[attempt]
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func testRetryTransport(next http.RoundTripper, waits *[]time.Duration) *retryTransport {
+	t := newRetryTransport(next, time.Second, 3, 10*time.Millisecond, 100*time.Millisecond, nil)
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return t
+}
+
+func testRetryRequest(t *testing.T, method, body string) *http.Request {
+	req, err := http.NewRequest(method, "http://netbox.example.com/api/ipam/prefixes/", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return req
+}
+
+func TestRetryTransport_retriesIdempotentRequests(t *testing.T) {
+	next := &testRoundTripper{statuses: []int{502, 503, 504, 200}}
+	var waits []time.Duration
+
+	resp, err := testRetryTransport(next, &waits).RoundTrip(testRetryRequest(t, http.MethodPut, `{"prefix":"10.0.0.0/24"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if len(next.bodies) != 4 {
+		t.Fatalf("expected 4 attempts, got %d", len(next.bodies))
+	}
+	for i, b := range next.bodies {
+		if b != `{"prefix":"10.0.0.0/24"}` {
+			t.Fatalf("attempt %d sent body %q", i, b)
+		}
+	}
+	for i, w := range waits {
+		max := 10 * time.Millisecond << uint(i)
+		if w < max/2 || w > max {
+			t.Fatalf("wait %d = %s, expected between %s and %s", i, w, max/2, max)
+		}
+	}
+}
+
+func TestRetryTransport_givesUpAfterMaxRetries(t *testing.T) {
+	next := &testRoundTripper{statuses: []int{503, 503, 503, 503, 503}}
+	var waits []time.Duration
+
+	resp, err := testRetryTransport(next, &waits).RoundTrip(testRetryRequest(t, http.MethodGet, ""))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if resp.StatusCode != 503 {
+		t.Fatalf("expected 503, got %d", resp.StatusCode)
+	}
+	if len(next.bodies) != 4 {
+		t.Fatalf("expected 4 attempts, got %d", len(next.bodies))
+	}
+}
+
+func TestRetryTransport_doesNotReplayPost(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+	cases := map[string]*testRoundTripper{
+		"bad gateway":      &testRoundTripper{statuses: []int{502, 200}},
+		"gateway timeout":  &testRoundTripper{statuses: []int{504, 200}},
+		"connection reset": &testRoundTripper{errors: []error{reset}},
+	}
+
+	for name, next := range cases {
+		var waits []time.Duration
+		testRetryTransport(next, &waits).RoundTrip(testRetryRequest(t, http.MethodPost, "{}"))
+
+		if len(next.bodies) != 1 {
+			t.Fatalf("%s: POST was sent %d times", name, len(next.bodies))
+		}
+	}
+}
+
+func TestRetryTransport_replaysPostNotReceived(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	cases := map[string]*testRoundTripper{
+		"too many requests":  &testRoundTripper{statuses: []int{429, 201}},
+		"connection refused": &testRoundTripper{errors: []error{refused}, statuses: []int{0, 201}},
+	}
+
+	for name, next := range cases {
+		var waits []time.Duration
+		resp, err := testRetryTransport(next, &waits).RoundTrip(testRetryRequest(t, http.MethodPost, `{"address":"10.0.0.1/32"}`))
+
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+		if resp.StatusCode != 201 {
+			t.Fatalf("%s: expected 201, got %d", name, resp.StatusCode)
+		}
+		if next.bodies[1] != `{"address":"10.0.0.1/32"}` {
+			t.Fatalf("%s: replayed body %q", name, next.bodies[1])
+		}
+	}
+}
+
+func TestRetryTransport_retriesConnectionReset(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	next := &testRoundTripper{errors: []error{reset, reset}}
+	var waits []time.Duration
+
+	if _, err := testRetryTransport(next, &waits).RoundTrip(testRetryRequest(t, http.MethodDelete, "")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(next.bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(next.bodies))
+	}
+}
+
+func TestRetryTransport_honoursRetryAfter(t *testing.T) {
+	rt := newRetryTransport(nil, time.Second, 3, time.Second, time.Minute, nil)
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if wait := rt.backoff(0, resp); wait != 7*time.Second {
+		t.Fatalf("expected 7s, got %s", wait)
+	}
+
+	resp.Header.Set("Retry-After", "3600")
+	if wait := rt.backoff(0, resp); wait != time.Minute {
+		t.Fatalf("expected wait capped at 1m, got %s", wait)
+	}
+}
+
+func TestRetryTransport_stopsBackoffWhenCancelled(t *testing.T) {
+	next := &testRoundTripper{statuses: []int{503, 200}}
+	rt := newRetryTransport(next, time.Second, 3, time.Hour, time.Hour, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := rt.RoundTrip(testRetryRequest(t, "GET", "").WithContext(ctx))
+	if err != context.Canceled {
+		t.Fatalf("expected the backoff to be cancelled, got %v", err)
+	}
+	if len(next.bodies) != 1 {
+		t.Fatalf("expected 1 attempt, got %d", len(next.bodies))
+	}
+}
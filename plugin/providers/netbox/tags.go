@@ -0,0 +1,63 @@
+package netbox
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// tagsSchema returns the schema of the tags attribute shared by every
+// resource whose Netbox object can be tagged.
+func tagsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Set:         schema.HashString,
+		Description: "Tags assigned to the object. The provider's default_tags are added to these.",
+	}
+}
+
+// expandTags returns the tags to send to Netbox: the configured tags merged
+// with the provider's default tags.
+func expandTags(d *schema.ResourceData, meta interface{}) []string {
+	tags := map[string]bool{}
+
+	for _, tag := range d.Get("tags").(*schema.Set).List() {
+		tags[tag.(string)] = true
+	}
+
+	for _, tag := range meta.(*ProviderNetboxClient).defaultTags {
+		tags[tag] = true
+	}
+
+	result := []string{}
+	for tag := range tags {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// flattenTags returns the value of the tags attribute for the tags read from
+// Netbox. Default tags are left out unless they are also configured on the
+// resource, so they don't show up as drift.
+func flattenTags(d *schema.ResourceData, meta interface{}, tags []string) *schema.Set {
+	configured := d.Get("tags").(*schema.Set)
+
+	defaults := map[string]bool{}
+	for _, tag := range meta.(*ProviderNetboxClient).defaultTags {
+		defaults[tag] = true
+	}
+
+	result := schema.NewSet(schema.HashString, nil)
+	for _, tag := range tags {
+		if defaults[tag] && !configured.Contains(tag) {
+			continue
+		}
+		result.Add(tag)
+	}
+
+	return result
+}
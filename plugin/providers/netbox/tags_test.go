@@ -0,0 +1,125 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+)
+
+func TestExpandTags(t *testing.T) {
+	meta := &ProviderNetboxClient{defaultTags: []string{"managed-by-terraform", "shared"}}
+
+	d := resourceNetboxIpamPrefix().TestResourceData()
+	d.Set("tags", []interface{}{"shared", "web"})
+
+	expected := []string{"managed-by-terraform", "shared", "web"}
+	if actual := expandTags(d, meta); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	if actual := expandTags(resourceNetboxIpamPrefix().TestResourceData(), &ProviderNetboxClient{}); actual == nil || len(actual) != 0 {
+		t.Fatalf("expected an empty, non-nil tag list, got %#v", actual)
+	}
+}
+
+func TestFlattenTags(t *testing.T) {
+	meta := &ProviderNetboxClient{defaultTags: []string{"managed-by-terraform", "shared"}}
+
+	d := resourceNetboxIpamPrefix().TestResourceData()
+	d.Set("tags", []interface{}{"shared", "web"})
+
+	var actual []string
+	for _, tag := range flattenTags(d, meta, []string{"managed-by-terraform", "shared", "web", "set-in-ui"}).List() {
+		actual = append(actual, tag.(string))
+	}
+	sort.Strings(actual)
+
+	// Default tags only appear when configured on the resource itself, while
+	// tags added outside of Terraform show up as drift.
+	expected := []string{"set-in-ui", "shared", "web"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResourceNetboxIpamPrefix_tags(t *testing.T) {
+	server := newTestNetboxServer(t, "2.5", map[string]testRoute{
+		"POST /api/ipam/prefixes/":   {status: http.StatusCreated, body: `{"id": 42, "prefix": "10.0.0.0/24", "tags": ["managed-by-terraform", "web"]}`},
+		"GET /api/ipam/prefixes/42/": {body: `{"id": 42, "prefix": "10.0.0.0/24", "tags": ["managed-by-terraform", "web", "set-in-ui"]}`},
+	})
+	defer server.Close()
+
+	meta := server.meta(t)
+	meta.defaultTags = []string{"managed-by-terraform"}
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamPrefix().Schema, map[string]interface{}{
+		"prefix": "10.0.0.0/24",
+		"tags":   []interface{}{"web"},
+	})
+
+	if err := resourceNetboxIpamPrefixCreate(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var sent struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(server.received()[0].body), &sent); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(sent.Tags, []string{"managed-by-terraform", "web"}) {
+		t.Fatalf("unexpected tags sent: %v", sent.Tags)
+	}
+
+	if err := resourceNetboxIpamPrefixRead(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if tags := d.Get("tags").(interface{ Len() int }).Len(); tags != 2 {
+		t.Fatalf("expected web and set-in-ui in state, got %v", d.Get("tags"))
+	}
+}
+
+func TestAPIVersionCompatibility_nestedTags(t *testing.T) {
+	server := newTestNetboxServer(t, "2.9", map[string]testRoute{
+		"GET /api/ipam/prefixes/42/": {body: `{"id": 42, "prefix": "10.0.0.0/24", "tags": [{"id": 1, "name": "web", "slug": "web", "color": "9e9e9e"}]}`},
+		"PUT /api/ipam/prefixes/42/": {body: `{"id": 42, "prefix": "10.0.0.0/24", "tags": [{"id": 1, "name": "web", "slug": "web", "color": "9e9e9e"}]}`},
+	})
+	defer server.Close()
+
+	c := server.meta(t)
+
+	read, err := c.client.IPAM.IPAMPrefixesRead(ipam.NewIPAMPrefixesReadParams().WithID(42), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(read.Payload.Tags, []string{"web"}) {
+		t.Fatalf("unexpected tags read: %v", read.Payload.Tags)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxIpamPrefix().Schema, map[string]interface{}{
+		"prefix": "10.0.0.0/24",
+		"tags":   []interface{}{"web"},
+	})
+	d.SetId("ipam/prefix/42")
+	d.Set("prefix_id", 42)
+
+	if err := resourceNetboxIpamPrefixUpdate(d, c); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var sent struct {
+		Tags []map[string]string `json:"tags"`
+	}
+	requests := server.received()
+	if err := json.Unmarshal([]byte(requests[len(requests)-1].body), &sent); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(sent.Tags) != 1 || sent.Tags[0]["name"] != "web" {
+		t.Fatalf("unexpected tags sent: %v", sent.Tags)
+	}
+}
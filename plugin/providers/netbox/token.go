@@ -0,0 +1,82 @@
+package netbox
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"github.com/go-openapi/runtime/logger"
+)
+
+// resolveAppID returns the API token from whichever of app_id, app_id_file
+// or app_id_command was configured.
+func (c *Config) resolveAppID() (string, error) {
+	sources := 0
+	for _, set := range []bool{c.AppID != "", c.AppIDFile != "", len(c.AppIDCommand) > 0} {
+		if set {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		return "", fmt.Errorf("only one of app_id, app_id_file and app_id_command may be set")
+	}
+
+	switch {
+	case c.AppIDFile != "":
+		content, err := ioutil.ReadFile(c.AppIDFile)
+		if err != nil {
+			return "", fmt.Errorf("reading app_id_file: %s", err)
+		}
+		return nonEmptyToken(string(content), "app_id_file "+c.AppIDFile)
+
+	case len(c.AppIDCommand) > 0:
+		var stdout, stderr bytes.Buffer
+
+		cmd := exec.Command(c.AppIDCommand[0], c.AppIDCommand[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		// Only stderr is reported, stdout holds the token.
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("running app_id_command %q: %s: %s", c.AppIDCommand[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return nonEmptyToken(stdout.String(), "app_id_command "+c.AppIDCommand[0])
+	}
+
+	return c.AppID, nil
+}
+
+func nonEmptyToken(raw, source string) (string, error) {
+	token := strings.TrimSpace(raw)
+
+	if token == "" {
+		return "", fmt.Errorf("%s returned an empty token", source)
+	}
+
+	return token, nil
+}
+
+// redactingLogger masks the API token in the request dumps the open API
+// runtime writes when SWAGGER_DEBUG or DEBUG is set.
+type redactingLogger struct {
+	logger.Logger
+	secret string
+}
+
+func (l redactingLogger) Printf(format string, args ...interface{}) {
+	l.Logger.Printf("%s", l.redact(fmt.Sprintf(format, args...)))
+}
+
+func (l redactingLogger) Debugf(format string, args ...interface{}) {
+	l.Logger.Debugf("%s", l.redact(fmt.Sprintf(format, args...)))
+}
+
+func (l redactingLogger) redact(s string) string {
+	if l.secret == "" {
+		return s
+	}
+	return strings.Replace(s, l.secret, "<redacted>", -1)
+}
@@ -0,0 +1,115 @@
+package netbox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+)
+
+func TestConfigResolveAppID(t *testing.T) {
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		cfg      Config
+		expected string
+	}{
+		{Config{AppID: "plain"}, "plain"},
+		{Config{AppIDFile: tokenFile}, "from-file"},
+		{Config{AppIDCommand: []string{"echo", " from-command "}}, "from-command"},
+	}
+
+	for _, tc := range cases {
+		actual, err := tc.cfg.resolveAppID()
+		if err != nil {
+			t.Fatalf("%#v: err: %s", tc.cfg, err)
+		}
+		if actual != tc.expected {
+			t.Fatalf("expected %q, got %q", tc.expected, actual)
+		}
+	}
+}
+
+func TestConfigResolveAppID_errors(t *testing.T) {
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	emptyFile := filepath.Join(dir, "empty")
+	if err := ioutil.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, cfg := range []Config{
+		{AppID: "plain", AppIDFile: emptyFile},
+		{AppIDFile: emptyFile, AppIDCommand: []string{"echo", "token"}},
+		{AppIDFile: filepath.Join(dir, "missing")},
+		{AppIDFile: emptyFile},
+		{AppIDCommand: []string{"false"}},
+		{AppIDCommand: []string{filepath.Join(dir, "no-such-helper")}},
+	} {
+		if _, err := cfg.resolveAppID(); err == nil {
+			t.Fatalf("%#v: expected error", cfg)
+		}
+	}
+}
+
+func TestConfigClient_appIDCommand(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 0, "results": []}`))
+	}))
+	defer server.Close()
+
+	meta, err := (&Config{Endpoint: server.URL, AppIDCommand: []string{"echo", "s3cret"}}).Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := meta.(*ProviderNetboxClient).client.IPAM.IPAMRirsList(ipam.NewIPAMRirsListParams(), nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if authorization != "Token s3cret" {
+		t.Fatalf("unexpected Authorization header %q", authorization)
+	}
+}
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *testLogger) Debugf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestRedactingLogger(t *testing.T) {
+	out := &testLogger{}
+	l := redactingLogger{Logger: out, secret: "s3cret"}
+
+	l.Debugf("GET /api/ HTTP/1.1\r\nAuthorization: Token %s\r\n", "s3cret")
+	l.Printf("%s", "no token here")
+
+	if strings.Contains(strings.Join(out.lines, ""), "s3cret") {
+		t.Fatalf("token leaked into logs: %q", out.lines)
+	}
+	if !strings.Contains(out.lines[0], "Authorization: Token <redacted>") {
+		t.Fatalf("unexpected log line %q", out.lines[0])
+	}
+}
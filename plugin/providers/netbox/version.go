@@ -0,0 +1,210 @@
+package netbox
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// apiVersion is the major.minor version of the Netbox REST API, as reported
+// in the API-Version header of every response. The zero value means the
+// version is unknown.
+type apiVersion struct {
+	Major int
+	Minor int
+}
+
+// clientAPIVersion is the API dialect spoken by the pinned go-netbox client.
+var clientAPIVersion = apiVersion{2, 5}
+
+func parseAPIVersion(raw string) (apiVersion, error) {
+	parts := strings.Split(strings.TrimSpace(raw), ".")
+
+	if len(parts) < 2 {
+		return apiVersion{}, fmt.Errorf("invalid API version %q", raw)
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return apiVersion{}, fmt.Errorf("invalid API version %q", raw)
+	}
+
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return apiVersion{}, fmt.Errorf("invalid API version %q", raw)
+	}
+
+	return apiVersion{Major: major, Minor: minor}, nil
+}
+
+func (v apiVersion) known() bool {
+	return v != apiVersion{}
+}
+
+// atLeast reports whether v is o or newer.
+func (v apiVersion) atLeast(o apiVersion) bool {
+	return v.Major > o.Major || (v.Major == o.Major && v.Minor >= o.Minor)
+}
+
+func (v apiVersion) String() string {
+	if !v.known() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// apiFeature is a capability of the Netbox API introduced in a given version.
+// Removed is set when a later version dropped it again.
+type apiFeature struct {
+	Name    string
+	Since   apiVersion
+	Removed apiVersion
+}
+
+var (
+	// Choice fields such as status are written and returned as slugs
+	// ("active") instead of integers (1).
+	featureChoiceSlugs = apiFeature{Name: "choice slugs", Since: apiVersion{2, 7}}
+
+	// Tags are written and returned as nested objects instead of names.
+	featureNestedTags = apiFeature{Name: "nested tags", Since: apiVersion{2, 9}}
+
+	// IP addresses have a DNS name.
+	featureDNSName = apiFeature{Name: "IP address DNS names", Since: apiVersion{2, 6}}
+
+	// IP addresses are assigned to a device or VM interface through
+	// assigned_object_type and assigned_object_id instead of interface.
+	featureAssignedObject = apiFeature{Name: "assigned objects", Since: apiVersion{2, 9}}
+
+	// Services listen on a list of ports instead of a single port.
+	featureServicePorts = apiFeature{Name: "multiple service ports", Since: apiVersion{2, 10}}
+
+	// Route targets, imported and exported by VRFs.
+	featureRouteTargets = apiFeature{Name: "route targets", Since: apiVersion{2, 10}}
+
+	// Aggregates belong to a tenant.
+	featureAggregateTenant = apiFeature{Name: "aggregate tenants", Since: apiVersion{2, 10}}
+
+	// Custom field definitions, with their type and choices, are listed
+	// under /extras/custom-fields/ and selection fields take the value of a
+	// choice instead of its ID.
+	featureCustomFieldDefinitions = apiFeature{Name: "custom field definitions", Since: apiVersion{2, 10}}
+
+	// Tenant groups are nested under a parent and have a description.
+	featureTenantGroupHierarchy = apiFeature{Name: "nested tenant groups", Since: apiVersion{2, 10}}
+)
+
+// supportedBy reports whether the feature is available on a server running
+// version v. An unknown version is assumed to support everything, leaving the
+// server to reject what it does not understand.
+func (f apiFeature) supportedBy(v apiVersion) bool {
+	if !v.known() {
+		return true
+	}
+	if f.Removed.known() && v.atLeast(f.Removed) {
+		return false
+	}
+	return v.atLeast(f.Since)
+}
+
+// knownBy reports whether a server running version v is known to provide the
+// feature. Whatever is translated from the 2.5 dialect of the generated
+// client, in bodies as in filters, follows this rule so that an undetected
+// version is spoken to consistently in that dialect.
+func (f apiFeature) knownBy(v apiVersion) bool {
+	return v.known() && f.supportedBy(v)
+}
+
+func (f apiFeature) requirement() string {
+	if f.Removed.known() {
+		return fmt.Sprintf("Netbox API >= %s and < %s", f.Since, f.Removed)
+	}
+	return fmt.Sprintf("Netbox API >= %s", f.Since)
+}
+
+// serverVersion holds the API version of the configured Netbox server. It is
+// detected when the provider is configured, and learnt from the first
+// response otherwise.
+type serverVersion struct {
+	mu sync.RWMutex
+	v  apiVersion
+}
+
+func (s *serverVersion) get() apiVersion {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.v
+}
+
+func (s *serverVersion) set(v apiVersion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.v = v
+}
+
+// learn records the version from a response header when none is known yet.
+func (s *serverVersion) learn(header http.Header) {
+	raw := header.Get("API-Version")
+	if raw == "" {
+		return
+	}
+
+	v, err := parseAPIVersion(raw)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.v.known() {
+		s.v = v
+	}
+}
+
+// detectAPIVersion requests the API root and reads the API-Version header.
+func detectAPIVersion(httpClient *http.Client, endpoint *apiEndpoint, appID string) (apiVersion, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint.URL("/"), nil)
+	if err != nil {
+		return apiVersion{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Token "+appID)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return apiVersion{}, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	raw := resp.Header.Get("API-Version")
+	if raw == "" {
+		return apiVersion{}, fmt.Errorf("%s did not return an API-Version header (HTTP %d)", endpoint, resp.StatusCode)
+	}
+
+	return parseAPIVersion(raw)
+}
+
+// apiVersion returns the version of the Netbox server, possibly unknown.
+func (c *ProviderNetboxClient) apiVersion() apiVersion {
+	if c.version == nil {
+		return apiVersion{}
+	}
+	return c.version.get()
+}
+
+// requireFeature returns a descriptive error when the attribute relies on a
+// feature the Netbox server does not provide.
+func (c *ProviderNetboxClient) requireFeature(f apiFeature, attribute string) error {
+	v := c.apiVersion()
+
+	if f.supportedBy(v) {
+		return nil
+	}
+
+	return fmt.Errorf("%s requires %s (%s), but the server runs Netbox API %s", attribute, f.requirement(), f.Name, v)
+}
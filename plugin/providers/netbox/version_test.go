@@ -0,0 +1,188 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tpretz/go-netbox/netbox/client/ipam"
+	"github.com/tpretz/go-netbox/netbox/models"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	cases := map[string]apiVersion{
+		"2.5":    {2, 5},
+		"2.10":   {2, 10},
+		" 3.0 ":  {3, 0},
+		"2.8.9":  {2, 8},
+		"v2.5":   {},
+		"2":      {},
+		"banana": {},
+	}
+
+	for raw, expected := range cases {
+		actual, err := parseAPIVersion(raw)
+		if expected.known() && err != nil {
+			t.Fatalf("%q: err: %s", raw, err)
+		}
+		if !expected.known() && err == nil {
+			t.Fatalf("%q: expected error", raw)
+		}
+		if actual != expected {
+			t.Fatalf("%q: expected %s, got %s", raw, expected, actual)
+		}
+	}
+}
+
+func TestAPIFeatureSupportedBy(t *testing.T) {
+	f := apiFeature{Name: "test", Since: apiVersion{2, 6}, Removed: apiVersion{2, 10}}
+
+	cases := map[apiVersion]bool{
+		{}:      true,
+		{2, 5}:  false,
+		{2, 6}:  true,
+		{2, 9}:  true,
+		{2, 10}: false,
+		{3, 0}:  false,
+	}
+
+	for v, expected := range cases {
+		if actual := f.supportedBy(v); actual != expected {
+			t.Fatalf("%s: expected %t, got %t", v, expected, actual)
+		}
+	}
+}
+
+func TestProviderNetboxClientRequireFeature(t *testing.T) {
+	c := &ProviderNetboxClient{version: &serverVersion{}}
+
+	if err := c.requireFeature(featureChoiceSlugs, "status"); err != nil {
+		t.Fatalf("unknown version should not be rejected: %s", err)
+	}
+
+	c.version.set(apiVersion{2, 5})
+	err := c.requireFeature(featureChoiceSlugs, "status")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), ">= 2.7") || !strings.Contains(err.Error(), "API 2.5") {
+		t.Fatalf("unclear error: %s", err)
+	}
+}
+
+func TestChoiceSetParse(t *testing.T) {
+	for raw, expected := range map[string]int64{"0": 0, "container": 0, "Active": 1, "3": 3} {
+		actual, err := prefixStatusChoices.parse(raw)
+		if err != nil {
+			t.Fatalf("%q: err: %s", raw, err)
+		}
+		if actual != expected {
+			t.Fatalf("%q: expected %d, got %d", raw, expected, actual)
+		}
+	}
+
+	for _, raw := range []string{"4", "available", ""} {
+		if _, err := prefixStatusChoices.parse(raw); err == nil {
+			t.Fatalf("%q: expected error", raw)
+		}
+	}
+}
+
+func TestChoiceSetFilter(t *testing.T) {
+	// Filters follow request bodies: slugs only when the server is known to
+	// take them.
+	for v, expected := range map[apiVersion]string{{2, 6}: "40", {2, 7}: "vip", {}: "40"} {
+		actual, err := ipAddressRoleChoices.filter("vip", v)
+		if err != nil {
+			t.Fatalf("%s: err: %s", v, err)
+		}
+		if actual != expected {
+			t.Fatalf("%s: expected %q, got %q", v, expected, actual)
+		}
+	}
+}
+
+// TestAPIVersionCompatibility runs the pinned client against recorded
+// payloads of each supported Netbox release.
+func TestAPIVersionCompatibility(t *testing.T) {
+	for _, version := range []string{"2.5", "2.6", "2.7", "2.8"} {
+		server := newTestNetboxServer(t, version, map[string]testRoute{
+			"GET /api/ipam/ip-addresses/1/": {body: testFixture(t, "api", version, "ip-address.json")},
+			"GET /api/ipam/prefixes/2/":     {body: testFixture(t, "api", version, "prefix.json")},
+			"POST /api/ipam/ip-addresses/":  {status: http.StatusCreated, body: testFixture(t, "api", version, "ip-address.json")},
+			"GET /api/ipam/prefixes/":       {body: `{"count": 1, "next": null, "previous": null, "results": [` + testFixture(t, "api", version, "prefix.json") + `]}`},
+		})
+
+		c := server.meta(t)
+
+		if actual := c.apiVersion().String(); actual != version {
+			t.Fatalf("%s: detected version %s", version, actual)
+		}
+
+		ip, err := c.client.IPAM.IPAMIPAddressesRead(ipam.NewIPAMIPAddressesReadParams().WithID(1), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", version, err)
+		}
+		if *ip.Payload.Status.Value != 1 || *ip.Payload.Role.Value != 40 {
+			t.Fatalf("%s: unexpected status %d / role %d", version, *ip.Payload.Status.Value, *ip.Payload.Role.Value)
+		}
+
+		prefix, err := c.client.IPAM.IPAMPrefixesRead(ipam.NewIPAMPrefixesReadParams().WithID(2), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", version, err)
+		}
+		if *prefix.Payload.Status.Value != 0 {
+			t.Fatalf("%s: unexpected prefix status %d", version, *prefix.Payload.Status.Value)
+		}
+
+		prefixes, err := c.client.IPAM.IPAMPrefixesList(ipam.NewIPAMPrefixesListParams(), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", version, err)
+		}
+		if *prefixes.Payload.Results[0].Status.Value != 0 {
+			t.Fatalf("%s: unexpected listed prefix status %d", version, *prefixes.Payload.Results[0].Status.Value)
+		}
+
+		address := "192.168.100.1/24"
+		_, err = c.client.IPAM.IPAMIPAddressesCreate(ipam.NewIPAMIPAddressesCreateParams().WithData(&models.IPAddressCreateUpdate{
+			Address: &address,
+			Status:  1,
+			Role:    40,
+			Tags:    []string{},
+		}), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", version, err)
+		}
+
+		requests := server.received()
+		var sent map[string]interface{}
+		if err := json.Unmarshal([]byte(requests[len(requests)-1].body), &sent); err != nil {
+			t.Fatalf("%s: err: %s", version, err)
+		}
+
+		expectedStatus, expectedRole := interface{}(float64(1)), interface{}(float64(40))
+		if version != "2.5" && version != "2.6" {
+			expectedStatus, expectedRole = "active", "vip"
+		}
+		if sent["status"] != expectedStatus || sent["role"] != expectedRole {
+			t.Fatalf("%s: sent status %v / role %v", version, sent["status"], sent["role"])
+		}
+
+		server.Close()
+	}
+}
+
+func TestAPIVersionCompatibility_unknownChoice(t *testing.T) {
+	fixture := strings.Replace(testFixture(t, "api", "2.8", "ip-address.json"), `"value": "active"`, `"value": "slaac"`, 1)
+
+	server := newTestNetboxServer(t, "2.10", map[string]testRoute{
+		"GET /api/ipam/ip-addresses/1/": {body: fixture},
+	})
+	defer server.Close()
+
+	_, err := server.meta(t).client.IPAM.IPAMIPAddressesRead(ipam.NewIPAMIPAddressesReadParams().WithID(1), nil)
+	if err == nil || !strings.Contains(err.Error(), `status "slaac"`) {
+		t.Fatalf("expected unsupported status error, got %v", err)
+	}
+}